@@ -0,0 +1,30 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blake2b
+
+func compress(d *digest, p []uint8) {
+	// Verifies if AVX2 or AVX is available, use optimized code path.
+	if avx2 {
+		compressAVX2(d, p)
+	} else if avx {
+		compressAVX(d, p)
+	} else if ssse3 {
+		compressSSE(d, p)
+	} else {
+		compressGeneric(d, p)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/syncthing/syncthing/lib/ignore"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/scanner"
 )
@@ -21,8 +22,13 @@ func main() {
 	log.SetOutput(os.Stdout)
 
 	standardBlocks := flag.Bool("s", false, "Use standard block size")
+	hashAlgorithm := flag.String("hash", "sha256", "Block hash algorithm (sha256, blake2b)")
+	ignoreFile := flag.String("ignores", "", "Explain how the path is matched by this ignore file")
 	flag.Parse()
 
+	var algo protocol.HashAlgorithm
+	algo.UnmarshalText([]byte(*hashAlgorithm))
+
 	path := flag.Arg(0)
 	if path == "" {
 		log.Fatal("Need one argument: path to check")
@@ -43,6 +49,10 @@ func main() {
 	log.Printf("  Time: %v (%d)", fi.ModTime(), fi.ModTime().Unix())
 	log.Println()
 
+	if *ignoreFile != "" {
+		explainIgnores(*ignoreFile, path, fi.IsDir())
+	}
+
 	if !fi.Mode().IsDir() && !fi.Mode().IsRegular() {
 		fi, err = os.Stat(path)
 		if err != nil {
@@ -57,7 +67,7 @@ func main() {
 	}
 
 	if fi.Mode().IsRegular() {
-		log.Println("Blocks:")
+		log.Printf("Blocks (%v):", algo)
 
 		fd, err := os.Open(path)
 		if err != nil {
@@ -68,7 +78,7 @@ func main() {
 		if *standardBlocks || blockSize < protocol.BlockSize {
 			blockSize = protocol.BlockSize
 		}
-		bs, err := scanner.Blocks(fd, blockSize, fi.Size(), nil)
+		bs, err := scanner.Blocks(fd, blockSize, algo, fi.Size(), nil)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -78,3 +88,29 @@ func main() {
 		}
 	}
 }
+
+func explainIgnores(ignoreFile, path string, isDir bool) {
+	pats := ignore.New(false)
+	if err := pats.Load(ignoreFile); err != nil {
+		log.Fatal(err)
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(ignoreFile), path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exp := pats.Explain(rel, isDir)
+
+	log.Println("Ignores:")
+	log.Printf("  Ignored: %v (cached: %v)", exp.Ignored, exp.Cached)
+	if exp.Matched {
+		file, line := exp.Pattern.Source()
+		log.Printf("  Pattern: %s", exp.Pattern.Text())
+		log.Printf("  Source: %s:%d", file, line)
+		log.Printf("  Regexp: %s", exp.Pattern)
+	} else {
+		log.Println("  Pattern: none")
+	}
+	log.Println()
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 func main() {
@@ -34,15 +35,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	folders := loadIndex(ldb, db.KeyTypeFolderIdx)
+	devices := loadIndex(ldb, db.KeyTypeDeviceIdx)
+
 	it := ldb.NewIterator(nil, nil)
 	var dev protocol.DeviceID
 	for it.Next() {
 		key := it.Key()
 		switch key[0] {
 		case db.KeyTypeDevice:
-			folder := nulString(key[1 : 1+64])
-			devBytes := key[1+64 : 1+64+32]
-			name := nulString(key[1+64+32:])
+			folder := folders[binary.BigEndian.Uint32(key[1:])]
+			devBytes := devices[binary.BigEndian.Uint32(key[1+4:])]
+			name := nulString(key[1+4+4:])
 			copy(dev[:], devBytes)
 			fmt.Printf("[device] F:%q N:%q D:%v\n", folder, name, dev)
 
@@ -54,14 +58,14 @@ func main() {
 			fmt.Printf("  N:%q\n  F:%#o\n  M:%d\n  V:%v\n  S:%d\n  B:%d\n", f.Name, f.Flags, f.Modified, f.Version, f.Size(), len(f.Blocks))
 
 		case db.KeyTypeGlobal:
-			folder := nulString(key[1 : 1+64])
-			name := nulString(key[1+64:])
+			folder := folders[binary.BigEndian.Uint32(key[1:])]
+			name := nulString(key[1+4:])
 			fmt.Printf("[global] F:%q N:%q V:%x\n", folder, name, it.Value())
 
 		case db.KeyTypeBlock:
-			folder := nulString(key[1 : 1+64])
-			hash := key[1+64 : 1+64+32]
-			name := nulString(key[1+64+32:])
+			folder := folders[binary.BigEndian.Uint32(key[1:])]
+			hash := key[1+4 : 1+4+32]
+			name := nulString(key[1+4+32:])
 			fmt.Printf("[block] F:%q H:%x N:%q I:%d\n", folder, hash, name, binary.BigEndian.Uint32(it.Value()))
 
 		case db.KeyTypeDeviceStatistic:
@@ -70,12 +74,31 @@ func main() {
 		case db.KeyTypeFolderStatistic:
 			fmt.Printf("[fstat]\n  %x\n  %x\n", it.Key(), it.Value())
 
+		case db.KeyTypeFolderIdx:
+			fmt.Printf("[folderidx] %d: %q\n", binary.BigEndian.Uint32(key[1:]), it.Value())
+
+		case db.KeyTypeDeviceIdx:
+			copy(dev[:], it.Value())
+			fmt.Printf("[deviceidx] %d: %v\n", binary.BigEndian.Uint32(key[1:]), dev)
+
 		default:
 			fmt.Printf("[???]\n  %x\n  %x\n", it.Key(), it.Value())
 		}
 	}
 }
 
+// loadIndex returns the folder or device index stored under the given key
+// type, mapping index numbers to their values.
+func loadIndex(ldb *leveldb.DB, keyType byte) map[uint32][]byte {
+	idx := make(map[uint32][]byte)
+	it := ldb.NewIterator(util.BytesPrefix([]byte{keyType}), nil)
+	defer it.Release()
+	for it.Next() {
+		idx[binary.BigEndian.Uint32(it.Key()[1:])] = append([]byte(nil), it.Value()...)
+	}
+	return idx
+}
+
 func nulString(bs []byte) string {
 	for i := range bs {
 		if bs[i] == 0 {
@@ -0,0 +1,551 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"gopkg.in/yaml.v2"
+)
+
+// Exit codes of the command line client.
+const (
+	cliExitOK          = 0 // success
+	cliExitError       = 1 // the command failed, e.g. an unknown folder
+	cliExitUsage       = 2 // invalid command or arguments
+	cliExitUnavailable = 3 // Syncthing could not be located or reached
+)
+
+const (
+	cliUsage      = "syncthing cli [options] <command> [arguments]"
+	cliExtraUsage = `
+Commands:
+
+  folders                           List the folders and their state
+  devices                           List the devices and their connections
+  scan <folder> [subdir...]         Rescan the folder, or parts of it
+  override <folder>                 Override remote changes in a master folder
+  pause <device>                    Pause the device
+  resume <device>                   Resume the device
+  ignores get <folder>              Show the ignore patterns of the folder
+  ignores set <folder> [file]       Set the ignore patterns of the folder from
+                                    the file, or standard input
+  need <folder> [device]            List the files needed by this device, or
+                                    by the given device
+  events [-since=id] [-follow]      Show events, waiting for new ones with
+                                    -follow
+  config get <resource>             Show a configuration resource, such as
+                                    "options" or "folders/default"
+  config set <resource> <json|->    Change a configuration resource, with the
+                                    JSON given or on standard input
+  apply [-dry-run] [-prune] <file>  Bring the folders, devices, options and
+                                    ignore patterns to the state described in
+                                    the YAML or JSON file, or standard input
+                                    for "-". With -prune, folders and devices
+                                    not in the file are removed.
+
+The address and API key of the GUI are read from the configuration, unless
+given as options.
+
+The exit code is 0 on success, 1 when the command fails, 2 for invalid usage
+and 3 when Syncthing cannot be reached.`
+)
+
+// A cliUsageError is an invalid command or argument.
+type cliUsageError string
+
+func (e cliUsageError) Error() string {
+	return string(e)
+}
+
+type cliContext struct {
+	client *cliClient
+	json   bool
+	in     io.Reader
+	out    io.Writer
+}
+
+type cliCommand func(c *cliContext, args []string) error
+
+var cliCommands = map[string]cliCommand{
+	"folders":  cliFolders,
+	"devices":  cliDevices,
+	"scan":     cliScan,
+	"override": cliOverride,
+	"pause":    cliPause,
+	"resume":   cliResume,
+	"ignores":  cliIgnores,
+	"need":     cliNeed,
+	"events":   cliEvents,
+	"config":   cliConfig,
+	"apply":    cliApply,
+}
+
+// cliMain runs the command line client with the given arguments, following
+// "syncthing cli", and returns the exit code.
+func cliMain(args []string, in io.Reader, out, errOut io.Writer) int {
+	var home, address, apiKey, format string
+	fs := flag.NewFlagSet("cli", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.StringVar(&home, "home", "", "Set configuration directory")
+	fs.StringVar(&address, "gui-address", guiAddress, "Override GUI address")
+	fs.StringVar(&apiKey, "gui-apikey", guiAPIKey, "Override GUI API key")
+	fs.StringVar(&format, "format", "table", "Output format, \"table\" or \"json\"")
+	fs.Usage = func() {
+		fmt.Fprintf(errOut, "Usage:\n  %s\n\nOptions:\n", cliUsage)
+		var options [][]string
+		fs.VisitAll(func(f *flag.Flag) {
+			options = append(options, []string{"  -" + f.Name + "=" + strconv.Quote(f.DefValue), f.Usage})
+		})
+		optionTable(errOut, options)
+		fmt.Fprintln(errOut, cliExtraUsage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return cliExitUsage
+	}
+	if format != "table" && format != "json" {
+		fmt.Fprintf(errOut, "Unknown output format %q\n", format)
+		return cliExitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return cliExitUsage
+	}
+	cmd, ok := cliCommands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(errOut, "Unknown command %q\n", fs.Arg(0))
+		return cliExitUsage
+	}
+
+	guiCfg, err := cliGUIConfig(home, address, apiKey)
+	if err != nil {
+		fmt.Fprintln(errOut, "Locating Syncthing:", err)
+		return cliExitUnavailable
+	}
+
+	c := &cliContext{
+		client: newCLIClient(guiCfg),
+		json:   format == "json",
+		in:     in,
+		out:    out,
+	}
+	if err := cmd(c, fs.Args()[1:]); err != nil {
+		fmt.Fprintln(errOut, err)
+		switch err.(type) {
+		case cliUsageError:
+			return cliExitUsage
+		case *cliConnError:
+			return cliExitUnavailable
+		default:
+			return cliExitError
+		}
+	}
+	return cliExitOK
+}
+
+// cliGUIConfig returns the GUI configuration from the config file, with the
+// overrides given on the command line. The file is not needed when both the
+// address and API key are given.
+func cliGUIConfig(home, address, apiKey string) (config.GUIConfiguration, error) {
+	if address != "" && apiKey != "" {
+		return overrideGUIConfig(config.GUIConfiguration{}, address, "", apiKey), nil
+	}
+
+	if home != "" {
+		baseDirs["config"] = home
+	}
+	if err := expandLocations(); err != nil {
+		return config.GUIConfiguration{}, err
+	}
+	cfg, err := config.Load(locations[locConfigFile], protocol.LocalDeviceID)
+	if err != nil {
+		return config.GUIConfiguration{}, err
+	}
+	return overrideGUIConfig(cfg.GUI(), address, "", apiKey), nil
+}
+
+// output writes the value as JSON, or the rows as a table with the given
+// header.
+func (c *cliContext) output(v interface{}, header []string, rows [][]string) error {
+	if c.json {
+		return c.outputJSON(v)
+	}
+	if header != nil {
+		rows = append([][]string{header}, rows...)
+	}
+	optionTable(c.out, rows)
+	return nil
+}
+
+func (c *cliContext) outputJSON(v interface{}) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out, "%s\n", bs)
+	return err
+}
+
+func cliArgs(args []string, min, max int, usage string) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return cliUsageError("Usage: syncthing cli " + usage)
+	}
+	return nil
+}
+
+func cliFolders(c *cliContext, args []string) error {
+	if err := cliArgs(args, 0, 0, "folders"); err != nil {
+		return err
+	}
+	cfg, err := c.client.Config()
+	if err != nil {
+		return err
+	}
+
+	var res []map[string]interface{}
+	var rows [][]string
+	for _, folder := range cfg.Folders {
+		status, err := c.client.FolderStatus(folder.ID)
+		if err != nil {
+			return err
+		}
+		res = append(res, map[string]interface{}{
+			"id":     folder.ID,
+			"path":   folder.RawPath,
+			"status": status,
+		})
+		rows = append(rows, []string{
+			folder.ID,
+			folder.RawPath,
+			fmt.Sprint(status["state"]),
+			fmt.Sprint(status["needFiles"]),
+			fmt.Sprint(status["needBytes"]),
+		})
+	}
+	return c.output(res, []string{"ID", "PATH", "STATE", "NEED FILES", "NEED BYTES"}, rows)
+}
+
+func cliDevices(c *cliContext, args []string) error {
+	if err := cliArgs(args, 0, 0, "devices"); err != nil {
+		return err
+	}
+	cfg, err := c.client.Config()
+	if err != nil {
+		return err
+	}
+	conns, err := c.client.Connections()
+	if err != nil {
+		return err
+	}
+
+	var res []map[string]interface{}
+	var rows [][]string
+	for _, device := range cfg.Devices {
+		id := device.DeviceID.String()
+		conn := conns[id]
+		res = append(res, map[string]interface{}{
+			"id":         id,
+			"name":       device.Name,
+			"connection": conn,
+		})
+		rows = append(rows, []string{
+			id,
+			device.Name,
+			strconv.FormatBool(conn.Connected),
+			strconv.FormatBool(conn.Paused),
+			conn.Address,
+		})
+	}
+	return c.output(res, []string{"ID", "NAME", "CONNECTED", "PAUSED", "ADDRESS"}, rows)
+}
+
+func cliScan(c *cliContext, args []string) error {
+	if err := cliArgs(args, 1, -1, "scan <folder> [subdir...]"); err != nil {
+		return err
+	}
+	return c.client.Scan(args[0], args[1:])
+}
+
+func cliOverride(c *cliContext, args []string) error {
+	if err := cliArgs(args, 1, 1, "override <folder>"); err != nil {
+		return err
+	}
+	return c.client.Override(args[0])
+}
+
+func cliPause(c *cliContext, args []string) error {
+	if err := cliArgs(args, 1, 1, "pause <device>"); err != nil {
+		return err
+	}
+	return c.client.Pause(args[0])
+}
+
+func cliResume(c *cliContext, args []string) error {
+	if err := cliArgs(args, 1, 1, "resume <device>"); err != nil {
+		return err
+	}
+	return c.client.Resume(args[0])
+}
+
+func cliIgnores(c *cliContext, args []string) error {
+	const usage = "ignores get <folder> | ignores set <folder> [file]"
+	if len(args) < 2 {
+		return cliUsageError("Usage: syncthing cli " + usage)
+	}
+
+	switch args[0] {
+	case "get":
+		if err := cliArgs(args, 2, 2, usage); err != nil {
+			return err
+		}
+		lines, err := c.client.Ignores(args[1])
+		if err != nil {
+			return err
+		}
+		if c.json {
+			return c.outputJSON(lines)
+		}
+		for _, line := range lines {
+			fmt.Fprintln(c.out, line)
+		}
+		return nil
+
+	case "set":
+		if err := cliArgs(args, 2, 3, usage); err != nil {
+			return err
+		}
+		in := c.in
+		if len(args) == 3 && args[2] != "-" {
+			fd, err := os.Open(args[2])
+			if err != nil {
+				return err
+			}
+			defer fd.Close()
+			in = fd
+		}
+		var lines []string
+		sc := bufio.NewScanner(in)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return err
+		}
+		return c.client.SetIgnores(args[1], lines)
+
+	default:
+		return cliUsageError("Usage: syncthing cli " + usage)
+	}
+}
+
+func cliNeed(c *cliContext, args []string) error {
+	if err := cliArgs(args, 1, 2, "need <folder> [device]"); err != nil {
+		return err
+	}
+	var device string
+	if len(args) == 2 {
+		device = args[1]
+	}
+	files, err := c.client.Need(args[0], device, 1, 1<<16)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(files))
+	for i, f := range files {
+		rows[i] = []string{f.Name, strconv.FormatInt(f.Size, 10), f.Modified.Format("2006-01-02 15:04:05")}
+	}
+	if files == nil {
+		files = []cliFile{}
+	}
+	return c.output(files, []string{"NAME", "SIZE", "MODIFIED"}, rows)
+}
+
+// cliEvents shows events one per line, as JSON objects in the JSON format,
+// since a table can not be aligned while following.
+func cliEvents(c *cliContext, args []string) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	since := fs.Int("since", 0, "")
+	follow := fs.Bool("follow", false, "")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		return cliUsageError("Usage: syncthing cli events [-since=id] [-follow]")
+	}
+
+	last := *since
+	for {
+		evs, err := c.client.Events(last)
+		if err != nil {
+			return err
+		}
+		for _, ev := range evs {
+			if c.json {
+				bs, _ := json.Marshal(ev)
+				fmt.Fprintf(c.out, "%s\n", bs)
+			} else {
+				var data bytes.Buffer
+				json.Compact(&data, ev.Data)
+				fmt.Fprintf(c.out, "%d %s %s %s\n", ev.ID, ev.Time.Format("2006-01-02 15:04:05"), ev.Type, data.Bytes())
+			}
+			last = ev.ID
+		}
+		if !*follow {
+			return nil
+		}
+	}
+}
+
+func cliConfig(c *cliContext, args []string) error {
+	const usage = "config get <resource> | config set <resource> <json|->"
+	if len(args) < 2 {
+		return cliUsageError("Usage: syncthing cli " + usage)
+	}
+
+	var res json.RawMessage
+	var err error
+	switch args[0] {
+	case "get":
+		if err := cliArgs(args, 2, 2, usage); err != nil {
+			return err
+		}
+		res, err = c.client.ConfigResource(args[1])
+	case "set":
+		if err := cliArgs(args, 3, 3, usage); err != nil {
+			return err
+		}
+		patch := []byte(args[2])
+		if args[2] == "-" {
+			if patch, err = ioutil.ReadAll(c.in); err != nil {
+				return err
+			}
+		}
+		var v interface{}
+		if err := json.Unmarshal(patch, &v); err != nil {
+			return cliUsageError("The configuration change is not valid JSON: " + err.Error())
+		}
+		res, err = c.client.PatchConfigResource(args[1], patch)
+	default:
+		return cliUsageError("Usage: syncthing cli " + usage)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Configuration resources do not fit a table; show them as JSON in
+	// either format.
+	var buf bytes.Buffer
+	json.Indent(&buf, res, "", "  ")
+	buf.WriteByte('\n')
+	_, err = c.out.Write(buf.Bytes())
+	return err
+}
+
+// cliApply brings the configuration to the desired state in a YAML or JSON
+// file, showing the changes made.
+func cliApply(c *cliContext, args []string) error {
+	const usage = "Usage: syncthing cli apply [-dry-run] [-prune] <file|->"
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	dryRun := fs.Bool("dry-run", false, "")
+	prune := fs.Bool("prune", false, "")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return cliUsageError(usage)
+	}
+
+	var bs []byte
+	var err error
+	if fs.Arg(0) == "-" {
+		bs, err = ioutil.ReadAll(c.in)
+	} else {
+		bs, err = ioutil.ReadFile(fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	desired, err := yamlToJSON(bs)
+	if err != nil {
+		return cliUsageError("Reading the desired configuration: " + err.Error())
+	}
+
+	res, err := c.client.ApplyConfig(desired, *dryRun, *prune)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.outputJSON(res)
+	}
+
+	for _, ch := range res.Changes {
+		switch ch.Action {
+		case "add":
+			fmt.Fprintf(c.out, "+ %s %s\n", ch.Section, ch.ID)
+		case "remove":
+			fmt.Fprintf(c.out, "- %s %s\n", ch.Section, ch.ID)
+		default:
+			name := strings.TrimSpace(ch.ID + " " + ch.Field)
+			fmt.Fprintf(c.out, "~ %s %s: %s -> %s\n", ch.Section, name, cliCompactJSON(ch.Old), cliCompactJSON(ch.New))
+		}
+	}
+	switch {
+	case len(res.Changes) == 0:
+		fmt.Fprintln(c.out, "No changes.")
+	case !res.Applied:
+		fmt.Fprintf(c.out, "Dry run; %d changes not applied.\n", len(res.Changes))
+	case res.RequiresRestart:
+		fmt.Fprintf(c.out, "Applied %d changes. Syncthing must be restarted for them to take effect.\n", len(res.Changes))
+	default:
+		fmt.Fprintf(c.out, "Applied %d changes.\n", len(res.Changes))
+	}
+	return nil
+}
+
+func cliCompactJSON(v interface{}) string {
+	bs, _ := json.Marshal(v)
+	return string(bs)
+}
+
+// yamlToJSON converts a YAML document, which may also be JSON as that is a
+// subset of YAML, to JSON.
+func yamlToJSON(bs []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(bs, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(yamlToJSONValue(v))
+}
+
+// yamlToJSONValue converts the maps decoded from YAML, which may have keys of
+// any type, to maps with string keys that can be encoded as JSON.
+func yamlToJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = yamlToJSONValue(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = yamlToJSONValue(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+// A cliClient makes typed calls to the REST API of a running instance.
+type cliClient struct {
+	base   string // scheme and host, without trailing slash
+	apiKey string
+	client *http.Client
+}
+
+// A cliAPIError is a non successful response from the REST API.
+type cliAPIError struct {
+	status int
+	body   string
+}
+
+func (e *cliAPIError) Error() string {
+	msg := strings.TrimSpace(e.body)
+	if msg == "" {
+		msg = http.StatusText(e.status)
+	}
+	return fmt.Sprintf("%d %s", e.status, msg)
+}
+
+// A cliConnError is a failure to talk to the instance at all.
+type cliConnError struct {
+	err error
+}
+
+func (e *cliConnError) Error() string {
+	return "connecting to Syncthing: " + e.err.Error()
+}
+
+func newCLIClient(guiCfg config.GUIConfiguration) *cliClient {
+	host, port, err := net.SplitHostPort(guiCfg.Address)
+	if err == nil {
+		// Listening on all addresses means we can reach it on localhost.
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			host = "127.0.0.1"
+		}
+		guiCfg.Address = net.JoinHostPort(host, port)
+	}
+
+	scheme := "http"
+	if guiCfg.UseTLS {
+		scheme = "https"
+	}
+
+	return &cliClient{
+		base:   scheme + "://" + guiCfg.Address,
+		apiKey: guiCfg.APIKey,
+		client: &http.Client{
+			Transport: &http.Transport{
+				// The GUI certificate is typically self signed.
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		},
+	}
+}
+
+// do performs the request and decodes the JSON response into res, unless it
+// is nil. Any non-2xx response is returned as a *cliAPIError.
+func (c *cliClient) do(method, path string, body io.Reader, res interface{}) error {
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("User-Agent", cliUserAgent+"/"+Version)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return &cliConnError{err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		bs, _ := ioutil.ReadAll(resp.Body)
+		return &cliAPIError{status: resp.StatusCode, body: string(bs)}
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+func (c *cliClient) get(path string, res interface{}) error {
+	return c.do("GET", path, nil, res)
+}
+
+func (c *cliClient) post(path string, data interface{}, res interface{}) error {
+	var body io.Reader
+	if data != nil {
+		bs, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(bs)
+	}
+	return c.do("POST", path, body, res)
+}
+
+func (c *cliClient) Config() (config.Configuration, error) {
+	var cfg config.Configuration
+	err := c.get("/rest/system/config", &cfg)
+	return cfg, err
+}
+
+func (c *cliClient) FolderStatus(folder string) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	err := c.get("/rest/db/status?folder="+url.QueryEscape(folder), &res)
+	return res, err
+}
+
+// cliConnection is the subset of the connection statistics we show.
+type cliConnection struct {
+	Connected     bool   `json:"connected"`
+	Paused        bool   `json:"paused"`
+	Address       string `json:"address"`
+	ClientVersion string `json:"clientVersion"`
+}
+
+func (c *cliClient) Connections() (map[string]cliConnection, error) {
+	var res struct {
+		Connections map[string]cliConnection `json:"connections"`
+	}
+	err := c.get("/rest/system/connections", &res)
+	return res.Connections, err
+}
+
+func (c *cliClient) Scan(folder string, subs []string) error {
+	qs := url.Values{}
+	qs.Set("folder", folder)
+	for _, sub := range subs {
+		qs.Add("sub", sub)
+	}
+	return c.post("/rest/db/scan?"+qs.Encode(), nil, nil)
+}
+
+func (c *cliClient) Override(folder string) error {
+	return c.post("/rest/db/override?folder="+url.QueryEscape(folder), nil, nil)
+}
+
+func (c *cliClient) Pause(device string) error {
+	return c.post("/rest/system/pause?device="+url.QueryEscape(device), nil, nil)
+}
+
+func (c *cliClient) Resume(device string) error {
+	return c.post("/rest/system/resume?device="+url.QueryEscape(device), nil, nil)
+}
+
+func (c *cliClient) Ignores(folder string) ([]string, error) {
+	var res map[string][]string
+	err := c.get("/rest/db/ignores?folder="+url.QueryEscape(folder), &res)
+	return res["ignore"], err
+}
+
+func (c *cliClient) SetIgnores(folder string, lines []string) error {
+	data := map[string][]string{"ignore": lines}
+	return c.post("/rest/db/ignores?folder="+url.QueryEscape(folder), data, nil)
+}
+
+// cliFile is a file in a need list.
+type cliFile struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// Need returns the files this device needs in the folder or, if a device is
+// given, the files that device needs.
+func (c *cliClient) Need(folder, device string, page, perpage int) ([]cliFile, error) {
+	qs := url.Values{}
+	qs.Set("folder", folder)
+	qs.Set("page", strconv.Itoa(page))
+	qs.Set("perpage", strconv.Itoa(perpage))
+
+	if device != "" {
+		qs.Set("device", device)
+		var res struct {
+			Files []cliFile `json:"files"`
+		}
+		err := c.get("/rest/db/remoteneed?"+qs.Encode(), &res)
+		return res.Files, err
+	}
+
+	var res struct {
+		Progress []cliFile `json:"progress"`
+		Queued   []cliFile `json:"queued"`
+		Rest     []cliFile `json:"rest"`
+	}
+	err := c.get("/rest/db/need?"+qs.Encode(), &res)
+	files := append(res.Progress, res.Queued...)
+	return append(files, res.Rest...), err
+}
+
+// cliEvent is an event as returned by the REST API.
+type cliEvent struct {
+	ID   int             `json:"id"`
+	Time time.Time       `json:"time"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Events returns the events after the given ID, waiting for one to happen if
+// there are none yet.
+func (c *cliClient) Events(since int) ([]cliEvent, error) {
+	var evs []cliEvent
+	err := c.get("/rest/events?since="+strconv.Itoa(since), &evs)
+	return evs, err
+}
+
+// ConfigResource returns a resource under /rest/config/, such as
+// "folders/default" or "options".
+func (c *cliClient) ConfigResource(path string) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.get("/rest/config/"+strings.Trim(path, "/"), &res)
+	return res, err
+}
+
+// PatchConfigResource applies the JSON patch to a resource under
+// /rest/config/ and returns the result.
+func (c *cliClient) PatchConfigResource(path string, patch []byte) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.do("PATCH", "/rest/config/"+strings.Trim(path, "/"), bytes.NewReader(patch), &res)
+	return res, err
+}
+
+type cliApplyResult struct {
+	Changes         []configChange `json:"changes"`
+	Applied         bool           `json:"applied"`
+	RequiresRestart bool           `json:"requiresRestart"`
+}
+
+// ApplyConfig brings the configuration to the desired state, given as JSON,
+// or only computes the changes to do so if dryRun is set.
+func (c *cliClient) ApplyConfig(desired []byte, dryRun, prune bool) (cliApplyResult, error) {
+	qs := url.Values{}
+	if dryRun {
+		qs.Set("dryrun", "true")
+	}
+	if prune {
+		qs.Set("prune", "true")
+	}
+	var res cliApplyResult
+	err := c.do("POST", "/rest/system/config/apply?"+qs.Encode(), bytes.NewReader(desired), &res)
+	return res, err
+}
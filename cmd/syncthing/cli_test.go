@@ -0,0 +1,208 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const cliTestKey = "abc123"
+
+// cliTestServer fakes the REST API, recording the requests made to it.
+type cliTestServer struct {
+	*httptest.Server
+	requests []string
+	bodies   []string
+}
+
+func newCLITestServer() *cliTestServer {
+	s := &cliTestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != cliTestKey {
+			http.Error(w, "Not Authorized", http.StatusForbidden)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		s.requests = append(s.requests, r.Method+" "+r.URL.String())
+		s.bodies = append(s.bodies, string(body))
+
+		switch r.URL.Path {
+		case "/rest/system/config":
+			w.Write([]byte(`{"folders": [{"id": "default", "path": "/data"}],
+				"devices": [{"deviceID": "GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY", "name": "other"}]}`))
+		case "/rest/db/status":
+			w.Write([]byte(`{"state": "idle", "needFiles": 2, "needBytes": 1024}`))
+		case "/rest/system/connections":
+			w.Write([]byte(`{"connections": {"GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY": {"connected": true, "address": "1.2.3.4:22000"}}}`))
+		case "/rest/db/override":
+			http.Error(w, "no such folder", 500)
+		case "/rest/db/need":
+			w.Write([]byte(`{"progress": [{"name": "a", "size": 1}], "queued": [], "rest": [{"name": "b", "size": 2}]}`))
+		case "/rest/events":
+			w.Write([]byte(`[{"id": 7, "time": "2015-10-16T12:53:20Z", "type": "Ping", "data": {"x": 1}}]`))
+		case "/rest/config/options":
+			w.Write([]byte(`{"maxSendKbps":100}`))
+		case "/rest/system/config/apply":
+			w.Write([]byte(`{"changes": [{"section": "folder", "id": "new", "action": "add"},
+				{"section": "options", "action": "change", "field": "maxSendKbps", "old": 0, "new": 100}], "applied": false}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	return s
+}
+
+func runCLI(s *cliTestServer, stdin string, args ...string) (int, string, string) {
+	var out, errOut bytes.Buffer
+	args = append([]string{"-gui-address", s.URL, "-gui-apikey", cliTestKey}, args...)
+	code := cliMain(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestCLIListings(t *testing.T) {
+	s := newCLITestServer()
+	defer s.Close()
+
+	code, out, _ := runCLI(s, "", "folders")
+	if code != cliExitOK {
+		t.Fatalf("folders exited %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || strings.Join(strings.Fields(lines[1]), " ") != "default /data idle 2 1024" {
+		t.Errorf("unexpected folders output:\n%s", out)
+	}
+
+	code, out, _ = runCLI(s, "", "-format", "json", "devices")
+	if code != cliExitOK {
+		t.Fatalf("devices exited %d", code)
+	}
+	var devices []struct {
+		Name       string
+		Connection cliConnection
+	}
+	if err := json.Unmarshal([]byte(out), &devices); err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].Name != "other" || !devices[0].Connection.Connected {
+		t.Errorf("unexpected devices output:\n%s", out)
+	}
+
+	code, out, _ = runCLI(s, "", "need", "default")
+	if code != cliExitOK || !strings.Contains(out, "a ") || !strings.Contains(out, "b ") {
+		t.Errorf("need exited %d with output:\n%s", code, out)
+	}
+
+	code, out, _ = runCLI(s, "", "events")
+	if code != cliExitOK || out != "7 2015-10-16 12:53:20 Ping {\"x\":1}\n" {
+		t.Errorf("events exited %d with output %q", code, out)
+	}
+}
+
+func TestCLIActions(t *testing.T) {
+	s := newCLITestServer()
+	defer s.Close()
+
+	if code, _, _ := runCLI(s, "", "scan", "default", "sub/dir"); code != cliExitOK {
+		t.Errorf("scan exited %d", code)
+	}
+	if code, _, _ := runCLI(s, "", "pause", "GYRZZQB"); code != cliExitOK {
+		t.Errorf("pause exited %d", code)
+	}
+	if code, _, _ := runCLI(s, "(?d)*.tmp\n*.bak\n", "ignores", "set", "default"); code != cliExitOK {
+		t.Errorf("ignores set exited %d", code)
+	}
+	if code, out, _ := runCLI(s, "", "config", "set", "options", `{"maxSendKbps": 100}`); code != cliExitOK || !strings.Contains(out, `"maxSendKbps": 100`) {
+		t.Errorf("config set exited %d with output %q", code, out)
+	}
+
+	expected := []string{
+		"POST /rest/db/scan?folder=default&sub=sub%2Fdir",
+		"POST /rest/system/pause?device=GYRZZQB",
+		"POST /rest/db/ignores?folder=default",
+		"PATCH /rest/config/options",
+	}
+	if strings.Join(s.requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected requests:\n%s", strings.Join(s.requests, "\n"))
+	}
+	if s.bodies[2] != `{"ignore":["(?d)*.tmp","*.bak"]}` {
+		t.Errorf("unexpected ignores body %q", s.bodies[2])
+	}
+	if s.bodies[3] != `{"maxSendKbps": 100}` {
+		t.Errorf("unexpected config body %q", s.bodies[3])
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	s := newCLITestServer()
+	defer s.Close()
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{nil, cliExitUsage},
+		{[]string{"frobnicate"}, cliExitUsage},
+		{[]string{"scan"}, cliExitUsage},
+		{[]string{"ignores", "list", "default"}, cliExitUsage},
+		{[]string{"config", "set", "options", "{"}, cliExitUsage},
+		{[]string{"-format", "xml", "folders"}, cliExitUsage},
+		{[]string{"override", "default"}, cliExitError},
+	} {
+		if code, _, _ := runCLI(s, "", tc.args...); code != tc.code {
+			t.Errorf("%v exited %d, expected %d", tc.args, code, tc.code)
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	args := []string{"-gui-address", s.URL, "-gui-apikey", "wrong", "folders"}
+	if code := cliMain(args, nil, &out, &errOut); code != cliExitError || !strings.Contains(errOut.String(), "403") {
+		t.Errorf("wrong API key exited %d with %q", code, errOut.String())
+	}
+
+	url := s.URL
+	s.Close()
+	args = []string{"-gui-address", url, "-gui-apikey", cliTestKey, "folders"}
+	if code := cliMain(args, nil, &out, &errOut); code != cliExitUnavailable {
+		t.Errorf("unreachable server exited %d", code)
+	}
+}
+
+func TestCLIApply(t *testing.T) {
+	s := newCLITestServer()
+	defer s.Close()
+
+	desired := "folders:\n  - id: new\n    path: /data/new\n    ignores: ['*.tmp']\noptions:\n  maxSendKbps: 100\n"
+	code, out, _ := runCLI(s, desired, "apply", "-dry-run", "-prune", "-")
+	if code != cliExitOK {
+		t.Fatalf("apply exited %d", code)
+	}
+	expected := "+ folder new\n~ options maxSendKbps: 0 -> 100\nDry run; 2 changes not applied.\n"
+	if out != expected {
+		t.Errorf("unexpected apply output:\n%s", out)
+	}
+
+	if s.requests[0] != "POST /rest/system/config/apply?dryrun=true&prune=true" {
+		t.Errorf("unexpected request %q", s.requests[0])
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(s.bodies[0]), &body); err != nil {
+		t.Fatal(err)
+	}
+	if folder := body["folders"].([]interface{})[0].(map[string]interface{}); folder["id"] != "new" || len(folder["ignores"].([]interface{})) != 1 {
+		t.Errorf("unexpected apply body %q", s.bodies[0])
+	}
+
+	if code, _, _ := runCLI(s, "folders: [", "apply", "-"); code != cliExitUsage {
+		t.Errorf("invalid YAML exited %d", code)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"mime"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -63,11 +64,14 @@ type apiSvc struct {
 	relaySvc        *relay.Svc
 	listener        net.Listener
 	fss             *folderSummarySvc
+	tokens          *apiTokenSet
+	sessions        *sessionStore
+	logins          *loginLimiter
 	stop            chan struct{}
 	systemConfigMut sync.Mutex
 }
 
-func newAPISvc(id protocol.DeviceID, cfg config.GUIConfiguration, assetDir string, m *model.Model, eventSub *events.BufferedSubscription, discoverer *discover.CachingMux, relaySvc *relay.Svc) (*apiSvc, error) {
+func newAPISvc(id protocol.DeviceID, cfg config.GUIConfiguration, assetDir string, ldb *db.Instance, m *model.Model, eventSub *events.BufferedSubscription, discoverer *discover.CachingMux, relaySvc *relay.Svc) (*apiSvc, error) {
 	svc := &apiSvc{
 		id:              id,
 		cfg:             cfg,
@@ -76,6 +80,9 @@ func newAPISvc(id protocol.DeviceID, cfg config.GUIConfiguration, assetDir strin
 		eventSub:        eventSub,
 		discoverer:      discoverer,
 		relaySvc:        relaySvc,
+		tokens:          newAPITokenSet(cfg.APIKey, cfg.APITokens),
+		sessions:        newSessionStore(ldb),
+		logins:          newLoginLimiter(),
 		systemConfigMut: sync.NewMutex(),
 	}
 
@@ -135,59 +142,12 @@ func (s *apiSvc) Serve() {
 
 	l.AddHandler(logger.LevelWarn, s.showGuiError)
 
-	// The GET handlers
-	getRestMux := http.NewServeMux()
-	getRestMux.HandleFunc("/rest/db/completion", s.getDBCompletion)              // device folder
-	getRestMux.HandleFunc("/rest/db/file", s.getDBFile)                          // folder file
-	getRestMux.HandleFunc("/rest/db/ignores", s.getDBIgnores)                    // folder
-	getRestMux.HandleFunc("/rest/db/need", s.getDBNeed)                          // folder [perpage] [page]
-	getRestMux.HandleFunc("/rest/db/status", s.getDBStatus)                      // folder
-	getRestMux.HandleFunc("/rest/db/browse", s.getDBBrowse)                      // folder [prefix] [dirsonly] [levels]
-	getRestMux.HandleFunc("/rest/events", s.getEvents)                           // since [limit]
-	getRestMux.HandleFunc("/rest/stats/device", s.getDeviceStats)                // -
-	getRestMux.HandleFunc("/rest/stats/folder", s.getFolderStats)                // -
-	getRestMux.HandleFunc("/rest/svc/deviceid", s.getDeviceID)                   // id
-	getRestMux.HandleFunc("/rest/svc/lang", s.getLang)                           // -
-	getRestMux.HandleFunc("/rest/svc/report", s.getReport)                       // -
-	getRestMux.HandleFunc("/rest/system/browse", s.getSystemBrowse)              // current
-	getRestMux.HandleFunc("/rest/system/config", s.getSystemConfig)              // -
-	getRestMux.HandleFunc("/rest/system/config/insync", s.getSystemConfigInsync) // -
-	getRestMux.HandleFunc("/rest/system/connections", s.getSystemConnections)    // -
-	getRestMux.HandleFunc("/rest/system/discovery", s.getSystemDiscovery)        // -
-	getRestMux.HandleFunc("/rest/system/error", s.getSystemError)                // -
-	getRestMux.HandleFunc("/rest/system/ping", s.restPing)                       // -
-	getRestMux.HandleFunc("/rest/system/status", s.getSystemStatus)              // -
-	getRestMux.HandleFunc("/rest/system/upgrade", s.getSystemUpgrade)            // -
-	getRestMux.HandleFunc("/rest/system/version", s.getSystemVersion)            // -
-
-	// The POST handlers
-	postRestMux := http.NewServeMux()
-	postRestMux.HandleFunc("/rest/db/prio", s.postDBPrio)                      // folder file [perpage] [page]
-	postRestMux.HandleFunc("/rest/db/ignores", s.postDBIgnores)                // folder
-	postRestMux.HandleFunc("/rest/db/override", s.postDBOverride)              // folder
-	postRestMux.HandleFunc("/rest/db/scan", s.postDBScan)                      // folder [sub...] [delay]
-	postRestMux.HandleFunc("/rest/system/config", s.postSystemConfig)          // <body>
-	postRestMux.HandleFunc("/rest/system/error", s.postSystemError)            // <body>
-	postRestMux.HandleFunc("/rest/system/error/clear", s.postSystemErrorClear) // -
-	postRestMux.HandleFunc("/rest/system/ping", s.restPing)                    // -
-	postRestMux.HandleFunc("/rest/system/reset", s.postSystemReset)            // [folder]
-	postRestMux.HandleFunc("/rest/system/restart", s.postSystemRestart)        // -
-	postRestMux.HandleFunc("/rest/system/shutdown", s.postSystemShutdown)      // -
-	postRestMux.HandleFunc("/rest/system/upgrade", s.postSystemUpgrade)        // -
-	postRestMux.HandleFunc("/rest/system/pause", s.postSystemPause)            // device
-	postRestMux.HandleFunc("/rest/system/resume", s.postSystemResume)          // device
-
-	// Debug endpoints, not for general use
-	getRestMux.HandleFunc("/rest/debug/peerCompletion", s.getPeerCompletion)
-
-	// A handler that splits requests between the two above and disables
-	// caching
-	restMux := noCacheMiddleware(getPostHandler(getRestMux, postRestMux))
-
 	// The main routing handler
 	mux := http.NewServeMux()
-	mux.Handle("/rest/", restMux)
+	mux.Handle("/rest/", s.restMux())
+	mux.Handle("/rest/config/", noCacheMiddleware(http.HandlerFunc(s.serveConfig)))
 	mux.HandleFunc("/qr/", s.getQR)
+	mux.Handle("/metrics", noCacheMiddleware(http.HandlerFunc(s.getMetrics)))
 
 	// Serve compiled in assets unless an asset directory was set (for development)
 	mux.Handle("/", embeddedStatic{
@@ -195,16 +155,24 @@ func (s *apiSvc) Serve() {
 		assets:   auto.Assets(),
 	})
 
+	// Limit requests made with API tokens to what the tokens permit.
+	handler := tokenScopeMiddleware(s.tokens, mux)
+
 	// Wrap everything in CSRF protection. The /rest prefix should be
 	// protected, other requests will grant cookies.
-	handler := csrfMiddleware(s.id.String()[:5], "/rest", s.cfg.APIKey, mux)
+	handler = csrfMiddleware(s.id.String()[:5], "/rest", s.tokens.valid, handler)
 
 	// Add our version and ID as a header to responses
 	handler = withDetailsMiddleware(s.id, handler)
 
 	// Wrap everything in basic auth, if user/password is set.
 	if len(s.cfg.User) > 0 && len(s.cfg.Password) > 0 {
-		handler = basicAuthAndSessionMiddleware("sessionid-"+s.id.String()[:5], s.cfg, handler)
+		handler = basicAuthAndSessionMiddleware(s.sessionCookieName(), s.cfg, s.tokens.valid, s.sessions, s.logins, handler)
+	}
+
+	// Serve metrics to anyone, if they don't require authentication.
+	if !s.cfg.MetricsAuth {
+		handler = metricsMiddleware(noCacheMiddleware(http.HandlerFunc(s.getMetrics)), handler)
 	}
 
 	// Redirect to HTTPS if we are supposed to
@@ -252,7 +220,12 @@ func (s *apiSvc) VerifyConfiguration(from, to config.Configuration) error {
 }
 
 func (s *apiSvc) CommitConfiguration(from, to config.Configuration) bool {
-	if to.GUI == from.GUI {
+	// Changed tokens take effect immediately. Anything else requires
+	// restarting the listener.
+	s.tokens.update(s.cfg.APIKey, to.GUI.APITokens)
+	fromGUI, toGUI := from.GUI, to.GUI
+	fromGUI.APITokens, toGUI.APITokens = nil, nil
+	if reflect.DeepEqual(toGUI, fromGUI) {
 		return true
 	}
 
@@ -274,12 +247,84 @@ func (s *apiSvc) CommitConfiguration(from, to config.Configuration) bool {
 		return false
 	}
 	s.cfg = to.GUI
+	s.tokens.update(s.cfg.APIKey, s.cfg.APITokens)
 
 	close(s.stop)
 
 	return true
 }
 
+// restMux returns the handler for the REST endpoints under /rest/, apart
+// from /rest/config/.
+func (s *apiSvc) restMux() http.Handler {
+	// The GET handlers
+	getRestMux := http.NewServeMux()
+	getRestMux.HandleFunc("/rest/db/completion", s.getDBCompletion)                    // device folder
+	getRestMux.HandleFunc("/rest/db/diff", s.getDBDiff)                                // folder a b [perpage] [page]
+	getRestMux.HandleFunc("/rest/db/download", s.getDBDownload)                        // folder file
+	getRestMux.HandleFunc("/rest/db/file", s.getDBFile)                                // folder file
+	getRestMux.HandleFunc("/rest/db/ignores", s.getDBIgnores)                          // folder
+	getRestMux.HandleFunc("/rest/db/ignores/explain", s.getDBIgnoresExplain)           // folder path
+	getRestMux.HandleFunc("/rest/db/need", s.getDBNeed)                                // folder [perpage] [page]
+	getRestMux.HandleFunc("/rest/db/remoteneed", s.getDBRemoteNeed)                    // folder device [perpage] [page]
+	getRestMux.HandleFunc("/rest/db/search", s.getDBSearch)                            // folder [q] [regex] [prefix] [minsize] [maxsize] [modifiedafter] [modifiedbefore] [deleted] [available] [perpage] [page]
+	getRestMux.HandleFunc("/rest/db/subscriptions", s.getDBSubscriptions)              // folder
+	getRestMux.HandleFunc("/rest/db/status", s.getDBStatus)                            // folder
+	getRestMux.HandleFunc("/rest/db/browse", s.getDBBrowse)                            // folder [prefix] [dirsonly] [levels]
+	getRestMux.HandleFunc("/rest/db/scrub", s.getDBScrub)                              // folder
+	getRestMux.HandleFunc("/rest/events", s.getEvents)                                 // since [limit]
+	getRestMux.HandleFunc("/rest/events/stream", s.getEventsStream)                    // [since] [mask] [events] [folder] [device]
+	getRestMux.HandleFunc("/rest/stats/device", s.getDeviceStats)                      // -
+	getRestMux.HandleFunc("/rest/stats/folder", s.getFolderStats)                      // -
+	getRestMux.HandleFunc("/rest/svc/deviceid", s.getDeviceID)                         // id
+	getRestMux.HandleFunc("/rest/svc/lang", s.getLang)                                 // -
+	getRestMux.HandleFunc("/rest/svc/report", s.getReport)                             // -
+	getRestMux.HandleFunc("/rest/system/browse", s.getSystemBrowse)                    // current
+	getRestMux.HandleFunc("/rest/system/config", s.getSystemConfig)                    // -
+	getRestMux.HandleFunc("/rest/system/config/diff", s.getSystemConfigDiff)           // from [to]
+	getRestMux.HandleFunc("/rest/system/config/insync", s.getSystemConfigInsync)       // -
+	getRestMux.HandleFunc("/rest/system/config/revisions", s.getSystemConfigRevisions) // -
+	getRestMux.HandleFunc("/rest/system/connections", s.getSystemConnections)          // -
+	getRestMux.HandleFunc("/rest/system/discovery", s.getSystemDiscovery)              // -
+	getRestMux.HandleFunc("/rest/system/error", s.getSystemError)                      // -
+	getRestMux.HandleFunc("/rest/system/ping", s.restPing)                             // -
+	getRestMux.HandleFunc("/rest/system/status", s.getSystemStatus)                    // -
+	getRestMux.HandleFunc("/rest/system/tokens", s.getSystemTokens)                    // -
+	getRestMux.HandleFunc("/rest/system/upgrade", s.getSystemUpgrade)                  // -
+	getRestMux.HandleFunc("/rest/system/version", s.getSystemVersion)                  // -
+
+	// The POST handlers
+	postRestMux := http.NewServeMux()
+	postRestMux.HandleFunc("/rest/db/prio", s.postDBPrio)                              // folder file [perpage] [page]
+	postRestMux.HandleFunc("/rest/db/ignores", s.postDBIgnores)                        // folder
+	postRestMux.HandleFunc("/rest/db/override", s.postDBOverride)                      // folder
+	postRestMux.HandleFunc("/rest/db/scan", s.postDBScan)                              // folder [sub...] [delay]
+	postRestMux.HandleFunc("/rest/db/scrub", s.postDBScrub)                            // folder
+	postRestMux.HandleFunc("/rest/db/subscriptions", s.postDBSubscriptions)            // folder <body>
+	postRestMux.HandleFunc("/rest/system/config", s.postSystemConfig)                  // <body>
+	postRestMux.HandleFunc("/rest/system/config/apply", s.postSystemConfigApply)       // [dryrun] [prune] <body>
+	postRestMux.HandleFunc("/rest/system/config/rollback", s.postSystemConfigRollback) // revision
+	postRestMux.HandleFunc("/rest/system/error", s.postSystemError)                    // <body>
+	postRestMux.HandleFunc("/rest/system/error/clear", s.postSystemErrorClear)         // -
+	postRestMux.HandleFunc("/rest/system/logout", s.postSystemLogout)                  // -
+	postRestMux.HandleFunc("/rest/system/ping", s.restPing)                            // -
+	postRestMux.HandleFunc("/rest/system/reset", s.postSystemReset)                    // [folder]
+	postRestMux.HandleFunc("/rest/system/restart", s.postSystemRestart)                // -
+	postRestMux.HandleFunc("/rest/system/shutdown", s.postSystemShutdown)              // -
+	postRestMux.HandleFunc("/rest/system/tokens", s.postSystemTokens)                  // <body>
+	postRestMux.HandleFunc("/rest/system/tokens/revoke", s.postSystemTokensRevoke)     // name
+	postRestMux.HandleFunc("/rest/system/upgrade", s.postSystemUpgrade)                // -
+	postRestMux.HandleFunc("/rest/system/pause", s.postSystemPause)                    // device
+	postRestMux.HandleFunc("/rest/system/resume", s.postSystemResume)                  // device
+
+	// Debug endpoints, not for general use
+	getRestMux.HandleFunc("/rest/debug/peerCompletion", s.getPeerCompletion)
+
+	// A handler that splits requests between the two above and disables
+	// caching
+	return noCacheMiddleware(getPostHandler(getRestMux, postRestMux))
+}
+
 func getPostHandler(get, post http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -464,14 +509,7 @@ func (s *apiSvc) getDBNeed(w http.ResponseWriter, r *http.Request) {
 
 	folder := qs.Get("folder")
 
-	page, err := strconv.Atoi(qs.Get("page"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-	perpage, err := strconv.Atoi(qs.Get("perpage"))
-	if err != nil || perpage < 1 {
-		perpage = 1 << 16
-	}
+	page, perpage := pagingParams(qs)
 
 	progress, queued, rest, total := s.model.NeedFolderFiles(folder, page, perpage)
 
@@ -483,6 +521,7 @@ func (s *apiSvc) getDBNeed(w http.ResponseWriter, r *http.Request) {
 		"total":    total,
 		"page":     page,
 		"perpage":  perpage,
+		"invalid":  s.model.InvalidFiles(folder),
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -522,6 +561,25 @@ func (s *apiSvc) getDBFile(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// getDBDownload serves the contents of the global version of a file, which
+// need not be available locally. Range requests are supported, so that
+// parts of large files can be read without fetching all of them.
+func (s *apiSvc) getDBDownload(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	file := qs.Get("file")
+
+	gf, err := s.model.OpenGlobal(folder, file)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+
+	name := filepath.Base(gf.Name())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, gf.ModTime(), gf)
+}
+
 func (s *apiSvc) getSystemConfig(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	json.NewEncoder(w).Encode(cfg.Raw())
@@ -554,21 +612,36 @@ func (s *apiSvc) postSystemConfig(w http.ResponseWriter, r *http.Request) {
 
 	// Fixup usage reporting settings
 
-	if curAcc := cfg.Options().URAccepted; to.Options.URAccepted > curAcc {
-		// UR was enabled
-		to.Options.URAccepted = usageReportVersion
-		to.Options.URUniqueID = randomString(8)
-	} else if to.Options.URAccepted < curAcc {
-		// UR was disabled
-		to.Options.URAccepted = -1
-		to.Options.URUniqueID = ""
+	fixupUsageReporting(&to.Options)
+
+	// Check the file system of newly added folders for case sensitivity
+
+	curFolders := cfg.Folders()
+	for i, folder := range to.Folders {
+		if _, ok := curFolders[folder.ID]; !ok {
+			to.Folders[i].CaseInsensitiveFS = osutil.IsCaseInsensitive(folder.Path())
+		}
 	}
 
 	// Activate and save
 
 	resp := cfg.Replace(to)
 	configInSync = !resp.RequiresRestart
-	cfg.Save()
+	cfg.SaveFrom(s.changeSource(r))
+}
+
+// fixupUsageReporting sets the usage reporting version and ID when usage
+// reporting is enabled or disabled in the new options.
+func fixupUsageReporting(to *config.OptionsConfiguration) {
+	if curAcc := cfg.Options().URAccepted; to.URAccepted > curAcc {
+		// UR was enabled
+		to.URAccepted = usageReportVersion
+		to.URUniqueID = randomString(8)
+	} else if to.URAccepted < curAcc {
+		// UR was disabled
+		to.URAccepted = -1
+		to.URUniqueID = ""
+	}
 }
 
 func (s *apiSvc) getSystemConfigInsync(w http.ResponseWriter, r *http.Request) {
@@ -737,6 +810,35 @@ func (s *apiSvc) getDBIgnores(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *apiSvc) getDBIgnoresExplain(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	exp, err := s.model.ExplainIgnore(qs.Get("folder"), qs.Get("path"))
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	res := map[string]interface{}{
+		"ignored": exp.Ignored,
+		"cached":  exp.Cached,
+		"pattern": nil,
+	}
+	if exp.Matched {
+		file, line := exp.Pattern.Source()
+		res["pattern"] = map[string]interface{}{
+			"pattern": exp.Pattern.Text(),
+			"regexp":  exp.Pattern.String(),
+			"include": exp.Pattern.Include(),
+			"file":    file,
+			"line":    line,
+		}
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
 func (s *apiSvc) postDBIgnores(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 
@@ -758,6 +860,46 @@ func (s *apiSvc) postDBIgnores(w http.ResponseWriter, r *http.Request) {
 	s.getDBIgnores(w, r)
 }
 
+func (s *apiSvc) getDBSubscriptions(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	local, remote, err := s.model.Subscriptions(qs.Get("folder"))
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	if local == nil {
+		local = []string{}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subscriptions": local,
+		"remote":        remote,
+	})
+}
+
+func (s *apiSvc) postDBSubscriptions(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	var data map[string][]string
+	err := json.NewDecoder(r.Body).Decode(&data)
+	r.Body.Close()
+
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	err = s.model.SetSubscriptions(qs.Get("folder"), data["subscriptions"])
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	s.getDBSubscriptions(w, r)
+}
+
 func (s *apiSvc) getEvents(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	sinceStr := qs.Get("since")
@@ -903,6 +1045,33 @@ func (s *apiSvc) postDBScan(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *apiSvc) getDBScrub(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	res, err := s.model.ScrubStatus(folder)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *apiSvc) postDBScrub(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	if _, err := s.model.ScrubStatus(folder); err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	// A scrub reads the entire folder and may take a very long time, so we
+	// don't wait for it. Progress is available from the GET endpoint.
+	if err := s.model.TriggerScrub(folder); err != nil {
+		http.Error(w, err.Error(), 409)
+		return
+	}
+}
+
 func (s *apiSvc) postDBPrio(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -1055,6 +1224,20 @@ func (s embeddedStatic) mimeTypeForFile(file string) string {
 	}
 }
 
+// pagingParams returns the page and number of items per page requested,
+// defaulting to everything on the first page.
+func pagingParams(qs url.Values) (page, perpage int) {
+	page, err := strconv.Atoi(qs.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perpage, err = strconv.Atoi(qs.Get("perpage"))
+	if err != nil || perpage < 1 {
+		perpage = 1 << 16
+	}
+	return page, perpage
+}
+
 func (s *apiSvc) toNeedSlice(fs []db.FileInfoTruncated) []jsonDBFileInfo {
 	res := make([]jsonDBFileInfo, len(fs))
 	for i, f := range fs {
@@ -0,0 +1,439 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// A desiredConfig is the state to bring the configuration to. Each folder and
+// device is decoded over the current one with the same ID, or over the
+// defaults for a new one, so that only the fields that matter need be given.
+// The options are likewise merged into the current options. A folder may
+// list its ignore patterns under "ignores", replacing the current ones.
+type desiredConfig struct {
+	Folders []json.RawMessage `json:"folders"`
+	Devices []json.RawMessage `json:"devices"`
+	Options json.RawMessage   `json:"options"`
+}
+
+// A configChange is one difference between two configurations. A changed
+// folder, device or the options give one change per changed field.
+type configChange struct {
+	Section string      `json:"section"` // "folder", "device", "options", "gui", "ignoredDevices" or "ignores"
+	ID      string      `json:"id,omitempty"`
+	Action  string      `json:"action"` // "add", "change" or "remove"
+	Field   string      `json:"field,omitempty"`
+	Old     interface{} `json:"old,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+}
+
+// postSystemConfigApply brings the configuration, and the ignore patterns of
+// the folders, to the posted desired state and responds with the changes
+// made. With dryrun the changes are only computed. With prune, folders and
+// devices that are not in the desired state are removed.
+func (s *apiSvc) postSystemConfigApply(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	dryRun := qs.Get("dryrun") != ""
+	prune := qs.Get("prune") != ""
+
+	var desired desiredConfig
+	err := json.NewDecoder(r.Body).Decode(&desired)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.systemConfigMut.Lock()
+	defer s.systemConfigMut.Unlock()
+
+	from := cfg.Raw().Copy()
+	to, ignores, err := s.desiredConfiguration(from, desired, prune)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changes := diffConfigs(from, to)
+	ignoreChanges := s.diffIgnores(to, ignores)
+	changes = append(changes, ignoreChanges...)
+
+	res := map[string]interface{}{
+		"changes":         changes,
+		"applied":         false,
+		"requiresRestart": false,
+	}
+
+	if !dryRun && len(changes) > 0 {
+		if len(changes) > len(ignoreChanges) {
+			resp := cfg.Replace(to)
+			if resp.ValidationError != nil {
+				http.Error(w, resp.ValidationError.Error(), http.StatusBadRequest)
+				return
+			}
+			if resp.RequiresRestart {
+				configInSync = false
+				res["requiresRestart"] = true
+			}
+			if err := cfg.SaveFrom(s.changeSource(r)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		for _, change := range ignoreChanges {
+			if err := s.setIgnores(to, change.ID, ignores[change.ID]); err != nil {
+				http.Error(w, fmt.Sprintf("setting ignores of %s: %v", change.ID, err), http.StatusInternalServerError)
+				return
+			}
+		}
+		res["applied"] = true
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(res)
+}
+
+// desiredConfiguration returns the configuration resulting from applying the
+// desired state to the current one, and the ignore patterns given per folder.
+func (s *apiSvc) desiredConfiguration(from config.Configuration, desired desiredConfig, prune bool) (config.Configuration, map[string][]string, error) {
+	to := from.Copy()
+	ignores := make(map[string][]string)
+
+	curFolders := make(map[string]int, len(to.Folders))
+	for i, fld := range to.Folders {
+		curFolders[fld.ID] = i
+	}
+	listedFolders := make(map[string]bool)
+	for _, raw := range desired.Folders {
+		var head struct {
+			ID      string    `json:"id"`
+			Ignores *[]string `json:"ignores"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return to, nil, err
+		}
+		if head.ID == "" {
+			return to, nil, fmt.Errorf("folder without an ID")
+		}
+		if listedFolders[head.ID] {
+			return to, nil, fmt.Errorf("folder %s listed twice", head.ID)
+		}
+		listedFolders[head.ID] = true
+
+		fld := config.FolderConfiguration{
+			ID:              head.ID,
+			RescanIntervalS: 60,
+			MinDiskFreePct:  1,
+		}
+		i, exists := curFolders[head.ID]
+		if exists {
+			fld = to.Folders[i]
+		}
+		if err := json.Unmarshal(raw, &fld); err != nil {
+			return to, nil, fmt.Errorf("folder %s: %v", head.ID, err)
+		}
+		if head.Ignores != nil {
+			lines := make([]string, len(*head.Ignores))
+			for j, line := range *head.Ignores {
+				lines[j] = strings.TrimSpace(line)
+			}
+			ignores[head.ID] = lines
+		}
+
+		if exists {
+			to.Folders[i] = fld
+		} else {
+			fld.CaseInsensitiveFS = osutil.IsCaseInsensitive(fld.Path())
+			fld.Devices = ensureFolderDevice(fld.Devices, s.id)
+			to.Folders = append(to.Folders, fld)
+		}
+	}
+
+	curDevices := make(map[protocol.DeviceID]int, len(to.Devices))
+	for i, dev := range to.Devices {
+		curDevices[dev.DeviceID] = i
+	}
+	listedDevices := map[protocol.DeviceID]bool{s.id: true}
+	for _, raw := range desired.Devices {
+		var head struct {
+			DeviceID protocol.DeviceID `json:"deviceID"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return to, nil, err
+		}
+		if head.DeviceID == (protocol.DeviceID{}) {
+			return to, nil, fmt.Errorf("device without an ID")
+		}
+		listedDevices[head.DeviceID] = true
+
+		dev := config.DeviceConfiguration{DeviceID: head.DeviceID}
+		i, exists := curDevices[head.DeviceID]
+		if exists {
+			dev = to.Devices[i]
+		}
+		if err := json.Unmarshal(raw, &dev); err != nil {
+			return to, nil, fmt.Errorf("device %s: %v", head.DeviceID, err)
+		}
+		if exists {
+			to.Devices[i] = dev
+		} else {
+			if len(dev.Addresses) == 0 {
+				dev.Addresses = []string{"dynamic"}
+			}
+			to.Devices = append(to.Devices, dev)
+		}
+	}
+
+	if len(desired.Options) > 0 {
+		if err := json.Unmarshal(desired.Options, &to.Options); err != nil {
+			return to, nil, fmt.Errorf("options: %v", err)
+		}
+		fixupUsageReporting(&to.Options)
+	}
+
+	if prune {
+		folders := to.Folders[:0]
+		for _, fld := range to.Folders {
+			if listedFolders[fld.ID] {
+				folders = append(folders, fld)
+			}
+		}
+		to.Folders = folders
+
+		devices := to.Devices[:0]
+		for _, dev := range to.Devices {
+			if listedDevices[dev.DeviceID] {
+				devices = append(devices, dev)
+			}
+		}
+		to.Devices = devices
+
+		for i, fld := range to.Folders {
+			fldDevices := fld.Devices[:0]
+			for _, dev := range fld.Devices {
+				if listedDevices[dev.DeviceID] {
+					fldDevices = append(fldDevices, dev)
+				}
+			}
+			to.Folders[i].Devices = fldDevices
+		}
+	}
+
+	return to, ignores, nil
+}
+
+// diffConfigs returns the changes to the folders, devices, options, GUI
+// settings and ignored devices between the two configurations, sorted by
+// folder and device ID.
+func diffConfigs(from, to config.Configuration) []configChange {
+	var changes []configChange
+
+	fromFolders := make(map[string]interface{})
+	for i := range from.Folders {
+		fromFolders[from.Folders[i].ID] = &from.Folders[i]
+	}
+	toFolders := make(map[string]interface{})
+	for i := range to.Folders {
+		toFolders[to.Folders[i].ID] = &to.Folders[i]
+	}
+	changes = append(changes, diffConfigSection("folder", fromFolders, toFolders)...)
+
+	fromDevices := make(map[string]interface{})
+	for i := range from.Devices {
+		fromDevices[from.Devices[i].DeviceID.String()] = &from.Devices[i]
+	}
+	toDevices := make(map[string]interface{})
+	for i := range to.Devices {
+		toDevices[to.Devices[i].DeviceID.String()] = &to.Devices[i]
+	}
+	changes = append(changes, diffConfigSection("device", fromDevices, toDevices)...)
+
+	changes = append(changes, diffConfigFields("options", "", &from.Options, &to.Options)...)
+	changes = append(changes, diffConfigFields("gui", "", &from.GUI, &to.GUI)...)
+
+	if len(from.IgnoredDevices) > 0 || len(to.IgnoredDevices) > 0 {
+		if !reflect.DeepEqual(from.IgnoredDevices, to.IgnoredDevices) {
+			changes = append(changes, configChange{
+				Section: "ignoredDevices",
+				Action:  "change",
+				Old:     from.IgnoredDevices,
+				New:     to.IgnoredDevices,
+			})
+		}
+	}
+
+	return changes
+}
+
+func diffConfigSection(section string, from, to map[string]interface{}) []configChange {
+	ids := make(map[string]struct{})
+	for id := range from {
+		ids[id] = struct{}{}
+	}
+	for id := range to {
+		ids[id] = struct{}{}
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var changes []configChange
+	for _, id := range sorted {
+		f, inFrom := from[id]
+		t, inTo := to[id]
+		switch {
+		case !inFrom:
+			changes = append(changes, configChange{Section: section, ID: id, Action: "add", New: t})
+		case !inTo:
+			changes = append(changes, configChange{Section: section, ID: id, Action: "remove", Old: f})
+		default:
+			changes = append(changes, diffConfigFields(section, id, f, t)...)
+		}
+	}
+	return changes
+}
+
+// diffConfigFields compares the values field by field, as named in their
+// JSON representation.
+func diffConfigFields(section, id string, from, to interface{}) []configChange {
+	fromFields := jsonFields(from)
+	toFields := jsonFields(to)
+
+	var names []string
+	for name := range fromFields {
+		names = append(names, name)
+	}
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []configChange
+	for _, name := range names {
+		if !reflect.DeepEqual(fromFields[name], toFields[name]) {
+			changes = append(changes, configChange{
+				Section: section,
+				ID:      id,
+				Action:  "change",
+				Field:   name,
+				Old:     fromFields[name],
+				New:     toFields[name],
+			})
+		}
+	}
+	return changes
+}
+
+func jsonFields(v interface{}) map[string]interface{} {
+	var fields map[string]interface{}
+	bs, _ := json.Marshal(v)
+	json.Unmarshal(bs, &fields)
+	return fields
+}
+
+// diffIgnores returns the changes to the ignore patterns of the folders,
+// sorted by folder ID.
+func (s *apiSvc) diffIgnores(to config.Configuration, ignores map[string][]string) []configChange {
+	var folders []string
+	for folder := range ignores {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+
+	var changes []configChange
+	for _, folder := range folders {
+		cur, _, err := s.model.GetIgnores(folder)
+		if err != nil {
+			// The folder is not running yet, so read the patterns it will
+			// pick up when it starts.
+			cur = readIgnoreFile(folderPath(to, folder))
+		}
+		if len(cur) == 0 && len(ignores[folder]) == 0 || reflect.DeepEqual(cur, ignores[folder]) {
+			continue
+		}
+		changes = append(changes, configChange{
+			Section: "ignores",
+			ID:      folder,
+			Action:  "change",
+			Old:     cur,
+			New:     ignores[folder],
+		})
+	}
+	return changes
+}
+
+// setIgnores sets the ignore patterns of the folder. A folder that is not
+// yet running has the patterns written for it to pick up when it starts,
+// before its first scan.
+func (s *apiSvc) setIgnores(to config.Configuration, folder string, lines []string) error {
+	if _, _, err := s.model.GetIgnores(folder); err == nil {
+		return s.model.SetIgnores(folder, lines)
+	}
+
+	dir := folderPath(to, folder)
+	if dir == "" {
+		return fmt.Errorf("no such folder")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ".stignore")
+	fd, err := osutil.CreateAtomic(path, 0644)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fmt.Fprintln(fd, line)
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	osutil.HideFile(path)
+	return nil
+}
+
+func folderPath(cfg config.Configuration, folder string) string {
+	for _, fld := range cfg.Folders {
+		if fld.ID == folder {
+			return fld.Path()
+		}
+	}
+	return ""
+}
+
+func readIgnoreFile(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	bs, err := ioutil.ReadFile(filepath.Join(dir, ".stignore"))
+	if err != nil || len(bs) == 0 {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(string(bs), "\n"), "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
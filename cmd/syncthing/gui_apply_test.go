@@ -0,0 +1,167 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func setupApplyAPI(t *testing.T) (*apiSvc, string, func()) {
+	s, cleanup := setupConfigAPI(t)
+	dir := filepath.Dir(cfg.ConfigPath())
+
+	m := model.NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(cfg.Folders()["default"])
+	s.model = m
+
+	return s, dir, cleanup
+}
+
+type applyResult struct {
+	Changes []configChange
+	Applied bool
+}
+
+func applyRequest(t *testing.T, s *apiSvc, query, body string) (int, applyResult) {
+	r, _ := http.NewRequest("POST", "/rest/system/config/apply?"+query, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	s.postSystemConfigApply(w, r)
+
+	var res applyResult
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w.Code, res
+}
+
+func changeNames(changes []configChange) []string {
+	var names []string
+	for _, ch := range changes {
+		names = append(names, strings.Join(strings.Fields(ch.Action+" "+ch.Section+" "+ch.ID+" "+ch.Field), " "))
+	}
+	return names
+}
+
+func TestConfigApply(t *testing.T) {
+	s, dir, cleanup := setupApplyAPI(t)
+	defer cleanup()
+
+	device2 := "GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY"
+	newPath := filepath.Join(dir, "new")
+	desired := `{
+		"folders": [
+			{"id": "default", "rescanIntervalS": 60},
+			{"id": "new", "path": ` + strconv.Quote(newPath) + `, "ignores": ["*.tmp", " *.bak "]}
+		],
+		"devices": [{"deviceID": "` + device2 + `", "name": "renamed"}],
+		"options": {"maxSendKbps": 100}
+	}`
+	expected := []string{
+		"add folder new",
+		"change device " + device2 + " name",
+		"change options maxSendKbps",
+		"change ignores new",
+	}
+
+	code, res := applyRequest(t, s, "dryrun=true", desired)
+	if code != http.StatusOK {
+		t.Fatalf("dry run gave status %d", code)
+	}
+	if names := changeNames(res.Changes); strings.Join(names, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected changes:\n%s", strings.Join(names, "\n"))
+	}
+	if res.Applied || len(cfg.Folders()) != 1 || cfg.Options().MaxSendKbps != 0 {
+		t.Error("dry run should not change the configuration")
+	}
+
+	code, res = applyRequest(t, s, "", desired)
+	if code != http.StatusOK || !res.Applied {
+		t.Fatalf("apply gave status %d, applied %v", code, res.Applied)
+	}
+	fld, ok := cfg.Folders()["new"]
+	if !ok || fld.RescanIntervalS != 60 || len(fld.Devices) != 1 {
+		t.Errorf("new folder not added with defaults, %+v", fld)
+	}
+	if cfg.Options().MaxSendKbps != 100 {
+		t.Error("options not applied")
+	}
+	bs, err := ioutil.ReadFile(filepath.Join(newPath, ".stignore"))
+	if err != nil || string(bs) != "*.tmp\n*.bak\n" {
+		t.Errorf("unexpected ignores %q (%v)", bs, err)
+	}
+
+	// Applying the same state again changes nothing.
+	code, res = applyRequest(t, s, "", desired)
+	if code != http.StatusOK || len(res.Changes) != 0 {
+		t.Errorf("reapplying gave status %d and changes %v", code, changeNames(res.Changes))
+	}
+}
+
+func TestConfigApplyPrune(t *testing.T) {
+	s, _, cleanup := setupApplyAPI(t)
+	defer cleanup()
+
+	device2, _ := protocol.DeviceIDFromString("GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY")
+	desired := `{"folders": [{"id": "default"}]}`
+
+	code, res := applyRequest(t, s, "", desired)
+	if code != http.StatusOK || len(res.Changes) != 0 {
+		t.Errorf("without prune, gave status %d and changes %v", code, changeNames(res.Changes))
+	}
+
+	code, res = applyRequest(t, s, "prune=true", desired)
+	if code != http.StatusOK {
+		t.Fatalf("prune gave status %d", code)
+	}
+	expected := "change folder default devices\nremove device " + device2.String()
+	if names := changeNames(res.Changes); strings.Join(names, "\n") != expected {
+		t.Errorf("unexpected changes:\n%s", strings.Join(names, "\n"))
+	}
+	if _, ok := cfg.Devices()[device2]; ok {
+		t.Error("unlisted device not pruned")
+	}
+	if _, ok := cfg.Devices()[protocol.LocalDeviceID]; !ok {
+		t.Error("the local device should never be pruned")
+	}
+	if devs := cfg.Folders()["default"].Devices; len(devs) != 1 || devs[0].DeviceID != protocol.LocalDeviceID {
+		t.Errorf("pruned device should no longer share the folder, %v", devs)
+	}
+}
+
+func TestConfigApplyInvalid(t *testing.T) {
+	s, _, cleanup := setupApplyAPI(t)
+	defer cleanup()
+
+	for _, desired := range []string{
+		`{"folders": [{"path": "/tmp"}]}`,
+		`{"folders": [{"id": "a"}, {"id": "a"}]}`,
+		`{"devices": [{"name": "no id"}]}`,
+		`{"options": {"maxSendKbps": "fast"}}`,
+		`not json`,
+	} {
+		if code, _ := applyRequest(t, s, "", desired); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d != expected 400", desired, code)
+		}
+	}
+	if len(cfg.Folders()) != 1 {
+		t.Error("invalid desired states should not change the configuration")
+	}
+}
@@ -11,42 +11,39 @@ import (
 	"encoding/base64"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/syncthing/syncthing/lib/config"
-	"github.com/syncthing/syncthing/lib/sync"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var (
-	sessions    = make(map[string]bool)
-	sessionsMut = sync.NewMutex()
-)
-
-func basicAuthAndSessionMiddleware(cookieName string, cfg config.GUIConfiguration, next http.Handler) http.Handler {
+func basicAuthAndSessionMiddleware(cookieName string, cfg config.GUIConfiguration, validAPIKey func(*http.Request) bool, sessions *sessionStore, logins *loginLimiter, next http.Handler) http.Handler {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if cfg.APIKey != "" && r.Header.Get("X-API-Key") == cfg.APIKey {
+		if validAPIKey(r) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		cookie, err := r.Cookie(cookieName)
-		if err == nil && cookie != nil {
-			sessionsMut.Lock()
-			_, ok := sessions[cookie.Value]
-			sessionsMut.Unlock()
-			if ok {
-				next.ServeHTTP(w, r)
-				return
-			}
+		if err == nil && cookie != nil && sessions.use(cookie.Value) {
+			next.ServeHTTP(w, r)
+			return
 		}
 
 		if debugHTTP {
 			l.Debugln("Sessionless HTTP request with authentication; this is expensive.")
 		}
 
+		addr := remoteHost(r)
+		if left := logins.lockedOut(addr); left > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(left/time.Second)+1))
+			http.Error(w, "Too many failed login attempts", 429)
+			return
+		}
+
 		error := func() {
 			time.Sleep(time.Duration(rand.Intn(100)+100) * time.Millisecond)
 			w.Header().Set("WWW-Authenticate", "Basic realm=\"Authorization Required\"")
@@ -62,34 +59,37 @@ func basicAuthAndSessionMiddleware(cookieName string, cfg config.GUIConfiguratio
 		hdr = hdr[6:]
 		bs, err := base64.StdEncoding.DecodeString(hdr)
 		if err != nil {
+			logins.failed(addr)
 			error()
 			return
 		}
 
 		fields := bytes.SplitN(bs, []byte(":"), 2)
 		if len(fields) != 2 {
+			logins.failed(addr)
 			error()
 			return
 		}
 
 		if string(fields[0]) != cfg.User {
+			logins.failed(addr)
 			error()
 			return
 		}
 
 		if err := bcrypt.CompareHashAndPassword([]byte(cfg.Password), fields[1]); err != nil {
+			logins.failed(addr)
 			error()
 			return
 		}
 
-		sessionid := randomString(32)
-		sessionsMut.Lock()
-		sessions[sessionid] = true
-		sessionsMut.Unlock()
+		logins.succeeded(addr)
 		http.SetCookie(w, &http.Cookie{
-			Name:   cookieName,
-			Value:  sessionid,
-			MaxAge: 0,
+			Name:     cookieName,
+			Value:    sessions.create(),
+			Path:     "/",
+			MaxAge:   int(sessionAbsoluteTimeout / time.Second),
+			HttpOnly: true,
 		})
 
 		next.ServeHTTP(w, r)
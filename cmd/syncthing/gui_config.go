@@ -0,0 +1,297 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// serveConfig serves the configuration as separate resources:
+//
+//	/rest/config/folders          GET
+//	/rest/config/folders/<id>     GET PUT PATCH DELETE
+//	/rest/config/devices          GET
+//	/rest/config/devices/<id>     GET PUT PATCH DELETE
+//	/rest/config/options          GET PUT PATCH
+//
+// PUT replaces or creates the resource, while PATCH changes only the fields
+// present in the request. Each resource has an ETag; a write with an
+// If-Match header is rejected if the resource has changed since.
+func (s *apiSvc) serveConfig(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/rest/config/")
+	kind, id := path, ""
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		kind, id = path[:i], path[i+1:]
+	}
+
+	// Writes are serialized with those of the whole config, so that the
+	// preconditions still hold when the change is committed.
+	s.systemConfigMut.Lock()
+	defer s.systemConfigMut.Unlock()
+
+	switch {
+	case kind == "folders" && id == "":
+		s.serveConfigList(w, r, cfg.Raw().Folders)
+	case kind == "folders":
+		s.serveConfigFolder(w, r, id)
+	case kind == "devices" && id == "":
+		s.serveConfigList(w, r, cfg.Raw().Devices)
+	case kind == "devices":
+		s.serveConfigDevice(w, r, id)
+	case kind == "options" && id == "":
+		s.serveConfigOptions(w, r)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func (s *apiSvc) serveConfigList(w http.ResponseWriter, r *http.Request, list interface{}) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sendConfigResource(w, list)
+}
+
+func (s *apiSvc) serveConfigFolder(w http.ResponseWriter, r *http.Request, id string) {
+	cur, exists := cfg.Folders()[id]
+	if !checkConfigPreconditions(w, r, &cur, exists) {
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		sendConfigResource(w, &cur)
+
+	case "PUT", "PATCH":
+		var fld config.FolderConfiguration
+		if !decodeConfigResource(w, r, &cur, &fld) {
+			return
+		}
+		if fld.ID == "" {
+			fld.ID = id
+		}
+		if fld.ID != id {
+			http.Error(w, "Folder ID does not match the URL", http.StatusBadRequest)
+			return
+		}
+		if !exists {
+			fld.CaseInsensitiveFS = osutil.IsCaseInsensitive(fld.Path())
+			fld.Devices = ensureFolderDevice(fld.Devices, s.id)
+		}
+		s.commitConfigResource(w, r, cfg.SetFolder(fld), func() interface{} {
+			fld := cfg.Folders()[id]
+			return &fld
+		})
+
+	case "DELETE":
+		s.commitConfigResource(w, r, cfg.RemoveFolder(id), nil)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *apiSvc) serveConfigDevice(w http.ResponseWriter, r *http.Request, id string) {
+	deviceID, err := protocol.DeviceIDFromString(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cur, exists := cfg.Devices()[deviceID]
+	if !checkConfigPreconditions(w, r, &cur, exists) {
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		sendConfigResource(w, &cur)
+
+	case "PUT", "PATCH":
+		var dev config.DeviceConfiguration
+		if !decodeConfigResource(w, r, &cur, &dev) {
+			return
+		}
+		if dev.DeviceID == (protocol.DeviceID{}) {
+			dev.DeviceID = deviceID
+		}
+		if dev.DeviceID != deviceID {
+			http.Error(w, "Device ID does not match the URL", http.StatusBadRequest)
+			return
+		}
+		if len(dev.Addresses) == 0 {
+			dev.Addresses = []string{"dynamic"}
+		}
+		s.commitConfigResource(w, r, cfg.SetDevice(dev), func() interface{} {
+			dev := cfg.Devices()[deviceID]
+			return &dev
+		})
+
+	case "DELETE":
+		if deviceID == s.id {
+			http.Error(w, "Cannot remove the local device", http.StatusBadRequest)
+			return
+		}
+		s.commitConfigResource(w, r, cfg.RemoveDevice(deviceID), nil)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *apiSvc) serveConfigOptions(w http.ResponseWriter, r *http.Request) {
+	cur := cfg.Options()
+	if !checkConfigPreconditions(w, r, &cur, true) {
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		sendConfigResource(w, &cur)
+
+	case "PUT", "PATCH":
+		var opts config.OptionsConfiguration
+		if !decodeConfigResource(w, r, &cur, &opts) {
+			return
+		}
+		fixupUsageReporting(&opts)
+		s.commitConfigResource(w, r, cfg.SetOptions(opts), func() interface{} {
+			opts := cfg.Options()
+			return &opts
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// configETag returns the entity tag of a configuration resource, which
+// changes whenever the resource does. Resources are passed as pointers, as
+// device IDs only marshal as text when addressable.
+func configETag(v interface{}) string {
+	bs, _ := json.Marshal(v)
+	hash := sha256.Sum256(bs)
+	return fmt.Sprintf(`"%x"`, hash[:12])
+}
+
+// checkConfigPreconditions returns true if the conditional headers of the
+// request are satisfied by the current resource. Otherwise it responds
+// accordingly, and returns false.
+func checkConfigPreconditions(w http.ResponseWriter, r *http.Request, cur interface{}, exists bool) bool {
+	if !exists && r.Method != "PUT" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return false
+	}
+
+	etag := ""
+	if exists {
+		etag = configETag(cur)
+	}
+
+	if match := r.Header.Get("If-Match"); match != "" && !etagMatches(match, etag) {
+		http.Error(w, "Precondition failed; the resource has changed", http.StatusPreconditionFailed)
+		return false
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		if r.Method == "GET" {
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+		} else {
+			http.Error(w, "Precondition failed; the resource exists", http.StatusPreconditionFailed)
+		}
+		return false
+	}
+	return true
+}
+
+// etagMatches returns true if the list of entity tags in a conditional
+// header matches the given tag, which is empty for a missing resource.
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeConfigResource decodes the request body into the new value of the
+// resource. For a PATCH, the new value starts out as a copy of the current
+// one, so that fields not in the request keep their values.
+func decodeConfigResource(w http.ResponseWriter, r *http.Request, cur, into interface{}) bool {
+	if r.Method == "PATCH" {
+		// Going through JSON gives a deep copy, so that decoding the patch
+		// can't touch the slices of the current configuration.
+		bs, err := json.Marshal(cur)
+		if err == nil {
+			err = json.Unmarshal(bs, into)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+	}
+
+	err := json.NewDecoder(r.Body).Decode(into)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// commitConfigResource saves the configuration after a change and responds
+// with the new value of the resource, if it still exists.
+func (s *apiSvc) commitConfigResource(w http.ResponseWriter, r *http.Request, resp config.CommitResponse, newValue func() interface{}) {
+	if resp.ValidationError != nil {
+		http.Error(w, resp.ValidationError.Error(), http.StatusBadRequest)
+		return
+	}
+	if resp.RequiresRestart {
+		configInSync = false
+	}
+	if err := cfg.SaveFrom(s.changeSource(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if newValue == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	sendConfigResource(w, newValue())
+}
+
+func sendConfigResource(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("ETag", configETag(v))
+	json.NewEncoder(w).Encode(v)
+}
+
+// ensureFolderDevice returns the folder devices, including the given one.
+func ensureFolderDevice(devices []config.FolderDeviceConfiguration, id protocol.DeviceID) []config.FolderDeviceConfiguration {
+	for _, dev := range devices {
+		if dev.DeviceID == id {
+			return devices
+		}
+	}
+	return append(devices, config.FolderDeviceConfiguration{DeviceID: id})
+}
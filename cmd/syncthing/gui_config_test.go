@@ -0,0 +1,185 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func setupConfigAPI(t *testing.T) (*apiSvc, func()) {
+	dir, err := ioutil.TempDir("", "syncthing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device2, _ := protocol.DeviceIDFromString("GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY")
+	cfg = config.Wrap(filepath.Join(dir, "config.xml"), config.Configuration{
+		Folders: []config.FolderConfiguration{{
+			ID:              "default",
+			RawPath:         dir,
+			RescanIntervalS: 60,
+			Devices: []config.FolderDeviceConfiguration{
+				{DeviceID: protocol.LocalDeviceID},
+				{DeviceID: device2},
+			},
+		}},
+		Devices: []config.DeviceConfiguration{
+			{DeviceID: protocol.LocalDeviceID, Name: "local"},
+			{DeviceID: device2, Name: "other"},
+		},
+	})
+
+	s := &apiSvc{
+		id:              protocol.LocalDeviceID,
+		systemConfigMut: sync.NewMutex(),
+	}
+	return s, func() { os.RemoveAll(dir) }
+}
+
+func configRequest(s *apiSvc, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	s.serveConfig(w, r)
+	return w
+}
+
+func TestConfigFolderETag(t *testing.T) {
+	s, cleanup := setupConfigAPI(t)
+	defer cleanup()
+
+	w := configRequest(s, "GET", "/rest/config/folders/default", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET gave status %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+
+	w = configRequest(s, "GET", "/rest/config/folders/default", "", "If-None-Match", etag)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("conditional GET gave status %d", w.Code)
+	}
+
+	w = configRequest(s, "PATCH", "/rest/config/folders/default", `{"rescanIntervalS": 120}`, "If-Match", etag)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH gave status %d: %s", w.Code, w.Body)
+	}
+	if w.Header().Get("ETag") == etag {
+		t.Error("ETag should change with the folder")
+	}
+	fld := cfg.Folders()["default"]
+	if fld.RescanIntervalS != 120 {
+		t.Errorf("rescan interval not patched, %d", fld.RescanIntervalS)
+	}
+	if len(fld.Devices) != 2 || fld.RawPath == "" {
+		t.Errorf("fields not in the patch should be kept, %+v", fld)
+	}
+
+	// A second writer with the old ETag is rejected.
+	w = configRequest(s, "PATCH", "/rest/config/folders/default", `{"rescanIntervalS": 30}`, "If-Match", etag)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("conflicting PATCH gave status %d", w.Code)
+	}
+	if cfg.Folders()["default"].RescanIntervalS != 120 {
+		t.Error("conflicting PATCH should not change the folder")
+	}
+}
+
+func TestConfigFolderCreateDelete(t *testing.T) {
+	s, cleanup := setupConfigAPI(t)
+	defer cleanup()
+
+	w := configRequest(s, "PATCH", "/rest/config/folders/new", `{}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("PATCH of a missing folder gave status %d", w.Code)
+	}
+
+	w = configRequest(s, "PUT", "/rest/config/folders/new", `{"id": "other"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("PUT with mismatched ID gave status %d", w.Code)
+	}
+
+	w = configRequest(s, "PUT", "/rest/config/folders/new", `{"path": "/tmp/new"}`, "If-None-Match", "*")
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT gave status %d: %s", w.Code, w.Body)
+	}
+	fld, ok := cfg.Folders()["new"]
+	if !ok {
+		t.Fatal("folder not created")
+	}
+	if len(fld.Devices) != 1 || fld.Devices[0].DeviceID != protocol.LocalDeviceID {
+		t.Errorf("new folder should be shared with the local device, %v", fld.Devices)
+	}
+
+	w = configRequest(s, "PUT", "/rest/config/folders/new", `{"path": "/tmp/new"}`, "If-None-Match", "*")
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("create only PUT of an existing folder gave status %d", w.Code)
+	}
+
+	w = configRequest(s, "DELETE", "/rest/config/folders/new", "")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("DELETE gave status %d", w.Code)
+	}
+	if _, ok := cfg.Folders()["new"]; ok {
+		t.Error("folder not removed")
+	}
+}
+
+func TestConfigDevicesAndOptions(t *testing.T) {
+	s, cleanup := setupConfigAPI(t)
+	defer cleanup()
+
+	w := configRequest(s, "GET", "/rest/config/devices", "")
+	var devs []config.DeviceConfiguration
+	if err := json.Unmarshal(w.Body.Bytes(), &devs); err != nil || len(devs) != 2 {
+		t.Fatalf("unexpected device list %s (%v)", w.Body, err)
+	}
+
+	path := "/rest/config/devices/" + devs[1].DeviceID.String()
+	w = configRequest(s, "PATCH", path, `{"name": "renamed"}`)
+	if w.Code != http.StatusOK || cfg.Devices()[devs[1].DeviceID].Name != "renamed" {
+		t.Errorf("PATCH gave status %d: %s", w.Code, w.Body)
+	}
+
+	w = configRequest(s, "DELETE", path, "")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("DELETE gave status %d", w.Code)
+	}
+	if len(cfg.Folders()["default"].Devices) != 1 {
+		t.Error("removed device should no longer share the folder")
+	}
+
+	w = configRequest(s, "DELETE", "/rest/config/devices/"+protocol.LocalDeviceID.String(), "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("removing the local device gave status %d", w.Code)
+	}
+
+	w = configRequest(s, "PATCH", "/rest/config/options", `{"maxSendKbps": 100}`)
+	if w.Code != http.StatusOK || cfg.Options().MaxSendKbps != 100 {
+		t.Errorf("PATCH gave status %d, %+v", w.Code, cfg.Options())
+	}
+
+	w = configRequest(s, "DELETE", "/rest/config/options", "")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE of options gave status %d", w.Code)
+	}
+}
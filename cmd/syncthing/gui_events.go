@@ -0,0 +1,155 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+// An eventFilter selects the events sent to a streaming client.
+type eventFilter struct {
+	mask   events.EventType
+	folder string
+	device string
+}
+
+func newEventFilter(r *http.Request) (eventFilter, error) {
+	qs := r.URL.Query()
+	f := eventFilter{
+		mask:   events.AllEvents,
+		folder: qs.Get("folder"),
+		device: qs.Get("device"),
+	}
+
+	if mask := qs.Get("mask"); mask != "" {
+		m, err := strconv.Atoi(mask)
+		if err != nil {
+			return f, fmt.Errorf("invalid mask %q", mask)
+		}
+		f.mask = events.EventType(m)
+	}
+	if names := qs.Get("events"); names != "" {
+		m, err := events.ParseEventTypes(names)
+		if err != nil {
+			return f, err
+		}
+		f.mask &= m
+	}
+
+	return f, nil
+}
+
+func (f eventFilter) matches(ev events.Event) bool {
+	if ev.Type&f.mask == 0 {
+		return false
+	}
+	if f.folder != "" && eventField(ev, "folder") != f.folder {
+		return false
+	}
+	if f.device != "" && eventField(ev, "device") != f.device && eventField(ev, "id") != f.device {
+		return false
+	}
+	return true
+}
+
+// eventField returns the string value of the named field in the event data,
+// if the data is a map with such a field.
+func eventField(ev events.Event, key string) string {
+	switch data := ev.Data.(type) {
+	case map[string]string:
+		return data[key]
+	case map[string]interface{}:
+		if s, ok := data[key].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// getEventsStream sends events as they happen, using server-sent events. A
+// client reconnecting with a Last-Event-ID header (or the since parameter)
+// gets the events it missed since, preceded by a "missed" event with the
+// number of events that are no longer available.
+func (s *apiSvc) getEventsStream(w http.ResponseWriter, r *http.Request) {
+	filter, err := newEventFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	last := -1
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		last, _ = strconv.Atoi(id)
+	} else if id := r.URL.Query().Get("since"); id != "" {
+		last, _ = strconv.Atoi(id)
+	}
+	if last > s.eventSub.LastID() {
+		// We've been restarted since, so the IDs aren't comparable.
+		last = -1
+	}
+	resuming := last >= 0
+	if !resuming {
+		// Start with the buffered events, like a poll without since.
+		last = 0
+	}
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	var evs []events.Event
+	for {
+		s.fss.gotEventRequest()
+
+		var missed int
+		evs, missed = s.eventSub.SinceMissed(last, evs[:0])
+		if missed > 0 && resuming {
+			if _, err := fmt.Fprintf(w, "event: missed\ndata: {\"missed\":%d}\n\n", missed); err != nil {
+				return
+			}
+		}
+		resuming = true
+
+		for _, ev := range evs {
+			last = ev.ID
+			if !filter.matches(ev) {
+				continue
+			}
+			if err := writeServerSentEvent(w, ev); err != nil {
+				return
+			}
+		}
+
+		// A comment keeps the connection alive, and tells us when the
+		// client has gone away, even if all events are filtered out.
+		if _, err := io.WriteString(w, ":\n\n"); err != nil {
+			return
+		}
+		f.Flush()
+	}
+}
+
+func writeServerSentEvent(w io.Writer, ev events.Event) error {
+	bs, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, bs)
+	return err
+}
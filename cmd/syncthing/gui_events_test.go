@@ -0,0 +1,67 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+func TestEventFilter(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/rest/events/stream?events=DeviceConnected,StateChanged&folder=default", nil)
+	filter, err := newEventFilter(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		ev      events.Event
+		matches bool
+	}{
+		{events.Event{Type: events.StateChanged, Data: map[string]interface{}{"folder": "default"}}, true},
+		{events.Event{Type: events.StateChanged, Data: map[string]interface{}{"folder": "other"}}, false},
+		{events.Event{Type: events.FolderSummary, Data: map[string]interface{}{"folder": "default"}}, false},
+		{events.Event{Type: events.DeviceConnected, Data: map[string]string{"id": "device"}}, false},
+	}
+	for i, tc := range tests {
+		if m := filter.matches(tc.ev); m != tc.matches {
+			t.Errorf("#%d: matches %v != %v", i, m, tc.matches)
+		}
+	}
+
+	r, _ = http.NewRequest("GET", "/rest/events/stream?mask=16&device=device", nil)
+	filter, err = newEventFilter(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filter.matches(events.Event{Type: events.DeviceConnected, Data: map[string]string{"id": "device"}}) {
+		t.Error("Event for the device should match")
+	}
+	if filter.matches(events.Event{Type: events.DeviceConnected, Data: map[string]string{"id": "other"}}) {
+		t.Error("Event for another device should not match")
+	}
+
+	r, _ = http.NewRequest("GET", "/rest/events/stream?events=Bogus", nil)
+	if _, err := newEventFilter(r); err == nil {
+		t.Error("Unexpected nil error for unknown event type")
+	}
+}
+
+func TestWriteServerSentEvent(t *testing.T) {
+	var buf bytes.Buffer
+	ev := events.Event{ID: 42, Type: events.Ping}
+	if err := writeServerSentEvent(&buf, ev); err != nil {
+		t.Fatal(err)
+	}
+	expected := "id: 42\nevent: Ping\ndata: {\"id\":42,\"time\":\"0001-01-01T00:00:00Z\",\"type\":\"Ping\",\"data\":null}\n\n"
+	if buf.String() != expected {
+		t.Errorf("Incorrect event %q != %q", buf.String(), expected)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+// The User-Agent prefix of requests made by the syncthing cli command.
+const cliUserAgent = "syncthing-cli"
+
+// changeSource returns who is making a configuration change with the
+// request, to be recorded in the configuration history.
+func (s *apiSvc) changeSource(r *http.Request) config.ChangeSource {
+	if s.tokens != nil {
+		if tok, ok := s.tokens.lookup(r); ok {
+			if strings.HasPrefix(r.UserAgent(), cliUserAgent) {
+				return config.ChangeSource{Type: config.SourceCLI, Name: tok.Name}
+			}
+			return config.ChangeSource{Type: config.SourceToken, Name: tok.Name}
+		}
+	}
+	return config.ChangeSource{Type: config.SourceGUI}
+}
+
+func (s *apiSvc) getSystemConfigRevisions(w http.ResponseWriter, r *http.Request) {
+	revs, err := cfg.Revisions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(revs)
+}
+
+// getSystemConfigDiff responds with the changes from one revision to
+// another, or to the current configuration if no other is given.
+func (s *apiSvc) getSystemConfigDiff(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	from, err := s.loadRevision(qs.Get("from"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	to := cfg.Raw()
+	if qs.Get("to") != "" {
+		to, err = s.loadRevision(qs.Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	}
+
+	changes := diffConfigs(from, to)
+	if changes == nil {
+		changes = []configChange{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// postSystemConfigRollback commits the configuration of an earlier revision
+// as the current one, apart from the GUI credentials and API tokens, and
+// responds with the changes made.
+func (s *apiSvc) postSystemConfigRollback(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("revision")
+
+	s.systemConfigMut.Lock()
+	defer s.systemConfigMut.Unlock()
+
+	to, err := s.loadRevision(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// The GUI credentials and API tokens are kept as they are, so that a
+	// rollback neither locks out the current user nor brings back a revoked
+	// token.
+	cur := cfg.GUI()
+	to.GUI.User = cur.User
+	to.GUI.Password = cur.Password
+	to.GUI.APIKey = cur.APIKey
+	to.GUI.APITokens = cur.APITokens
+
+	changes := diffConfigs(cfg.Raw(), to)
+	if changes == nil {
+		changes = []configChange{}
+	}
+
+	resp := cfg.Replace(to)
+	if resp.ValidationError != nil {
+		http.Error(w, resp.ValidationError.Error(), http.StatusBadRequest)
+		return
+	}
+	if resp.RequiresRestart {
+		configInSync = false
+	}
+
+	src := s.changeSource(r)
+	src.Reason = "rollback to revision " + id
+	if err := cfg.SaveFrom(src); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"changes":         changes,
+		"requiresRestart": resp.RequiresRestart,
+	})
+}
+
+func (s *apiSvc) loadRevision(id string) (config.Configuration, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return config.Configuration{}, fmt.Errorf("invalid revision %q", id)
+	}
+	return cfg.LoadRevision(n, s.id)
+}
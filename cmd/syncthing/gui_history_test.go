@@ -0,0 +1,124 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func historyRequest(handler http.HandlerFunc, method, path string) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestConfigHistoryRollback(t *testing.T) {
+	s, cleanup := setupConfigAPI(t)
+	defer cleanup()
+	s.tokens = newAPITokenSet("abc123", nil)
+
+	device2 := "GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY"
+	opts := cfg.Options()
+	opts.ConfigRevisions = 10
+	cfg.SetOptions(opts)
+	gui := cfg.GUI()
+	gui.APIKey = "abc123"
+	cfg.SetGUI(gui)
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Continue with the configuration as loaded on startup, so that it is
+	// comparable to the revisions.
+	var err error
+	cfg, err = config.Load(cfg.ConfigPath(), protocol.LocalDeviceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := configRequest(s, "PATCH", "/rest/config/devices/"+device2, `{"name": "renamed"}`); w.Code != http.StatusOK {
+		t.Fatalf("PATCH gave status %d", w.Code)
+	}
+	if w := configRequest(s, "DELETE", "/rest/config/folders/default", "", "X-API-Key", "abc123", "User-Agent", "syncthing-cli/v0.12.0"); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE gave status %d", w.Code)
+	}
+
+	w := historyRequest(s.getSystemConfigRevisions, "GET", "/rest/system/config/revisions")
+	var revs []config.Revision
+	if err := json.Unmarshal(w.Body.Bytes(), &revs); err != nil {
+		t.Fatal(err)
+	}
+	expected := []config.ChangeSource{
+		{Type: config.SourceSyncthing},
+		{Type: config.SourceGUI},
+		{Type: config.SourceCLI, Name: "apikey"},
+	}
+	if len(revs) != len(expected) {
+		t.Fatalf("%d revisions != expected %d", len(revs), len(expected))
+	}
+	for i, rev := range revs {
+		if rev.ID != i+1 || rev.Source != expected[i] {
+			t.Errorf("unexpected revision %+v", rev)
+		}
+	}
+
+	w = historyRequest(s.getSystemConfigDiff, "GET", "/rest/system/config/diff?from=1&to=3")
+	var changes []configChange
+	if err := json.Unmarshal(w.Body.Bytes(), &changes); err != nil {
+		t.Fatal(err)
+	}
+	if names := changeNames(changes); strings.Join(names, "\n") != "remove folder default\nchange device "+device2+" name" {
+		t.Errorf("unexpected changes:\n%s", strings.Join(names, "\n"))
+	}
+	if w := historyRequest(s.getSystemConfigDiff, "GET", "/rest/system/config/diff?from=3"); strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("latest revision should not differ from the current configuration, %s", w.Body.String())
+	}
+
+	// Credentials changed after the revision are kept.
+	gui = cfg.GUI()
+	gui.User = "admin"
+	gui.APITokens = []config.APIToken{{Name: "deploy", Token: "t1", Scope: config.ScopeAdmin}}
+	cfg.SetGUI(gui)
+
+	if w := historyRequest(s.postSystemConfigRollback, "POST", "/rest/system/config/rollback?revision=1"); w.Code != http.StatusOK {
+		t.Fatalf("rollback gave status %d", w.Code)
+	}
+	if _, ok := cfg.Folders()["default"]; !ok {
+		t.Error("removed folder should be back")
+	}
+	for _, dev := range cfg.Raw().Devices {
+		if dev.DeviceID.String() == device2 && dev.Name != "other" {
+			t.Errorf("device name %q should be rolled back", dev.Name)
+		}
+	}
+
+	if gui := cfg.GUI(); gui.APIKey != "abc123" || gui.User != "admin" || len(gui.APITokens) != 1 {
+		t.Errorf("GUI credentials and tokens should be kept, %+v", gui)
+	}
+
+	revs, _ = cfg.Revisions()
+	if last := revs[len(revs)-1]; last.ID != 4 || last.Source.Type != config.SourceGUI || last.Source.Reason != "rollback to revision 1" {
+		t.Errorf("unexpected rollback revision %+v", last)
+	}
+
+	for _, path := range []string{
+		"/rest/system/config/rollback?revision=99",
+		"/rest/system/config/rollback?revision=latest",
+	} {
+		if w := historyRequest(s.postSystemConfigRollback, "POST", path); w.Code != http.StatusNotFound {
+			t.Errorf("%s gave status %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// getDBRemoteNeed lists the files in the global index that the device is
+// missing, according to the index it has announced to us.
+func (s *apiSvc) getDBRemoteNeed(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	page, perpage := pagingParams(qs)
+
+	device, err := protocol.DeviceIDFromString(qs.Get("device"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	files, total, err := s.model.RemoteNeedFolderFiles(device, folder, page, perpage)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+
+	output := map[string]interface{}{
+		"files":   s.toNeedSlice(files),
+		"total":   total,
+		"page":    page,
+		"perpage": perpage,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(output)
+}
+
+// getDBDiff lists the files on which the indexes of the folder of two
+// devices differ. Either device may be this one.
+func (s *apiSvc) getDBDiff(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	page, perpage := pagingParams(qs)
+
+	a, err := protocol.DeviceIDFromString(qs.Get("a"))
+	if err != nil {
+		http.Error(w, "a: "+err.Error(), 400)
+		return
+	}
+	b, err := protocol.DeviceIDFromString(qs.Get("b"))
+	if err != nil {
+		http.Error(w, "b: "+err.Error(), 400)
+		return
+	}
+
+	diffs, total, err := s.model.FolderDiff(folder, a, b, page, perpage)
+	if err != nil {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+
+	jsonDiffs := make([]jsonFileDiff, len(diffs))
+	for i, d := range diffs {
+		jsonDiffs[i] = jsonFileDiff(d)
+	}
+	output := map[string]interface{}{
+		"files":   jsonDiffs,
+		"total":   total,
+		"page":    page,
+		"perpage": perpage,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(output)
+}
+
+type jsonFileDiff model.FileDiff
+
+func (d jsonFileDiff) MarshalJSON() ([]byte, error) {
+	side := func(f *db.FileInfoTruncated) interface{} {
+		if f == nil {
+			return nil
+		}
+		return jsonDBFileInfo(*f)
+	}
+	return json.Marshal(map[string]interface{}{
+		"name":     d.Name,
+		"a":        side(d.A),
+		"b":        side(d.B),
+		"relation": d.Relation,
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestGetDBRemoteNeedAndDiff(t *testing.T) {
+	device2, _ := protocol.DeviceIDFromString("GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY")
+	fcfg := config.FolderConfiguration{
+		ID:      "default",
+		RawPath: "testdata",
+		Devices: []config.FolderDeviceConfiguration{{DeviceID: device2}},
+	}
+	cfg = config.Wrap("/tmp/test", config.Configuration{
+		Folders: []config.FolderConfiguration{fcfg},
+		Devices: []config.DeviceConfiguration{{DeviceID: device2}},
+	})
+	m := model.NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+	s := &apiSvc{model: m}
+
+	local := protocol.LocalDeviceID.String()
+	other := device2.String()
+	for query, status := range map[string]int{
+		"/rest/db/remoteneed?folder=default&device=" + other:      http.StatusOK,
+		"/rest/db/remoteneed?folder=default&device=invalid":       http.StatusBadRequest,
+		"/rest/db/remoteneed?folder=nonexistent&device=" + other:  http.StatusNotFound,
+		"/rest/db/diff?folder=default&a=" + local + "&b=" + other: http.StatusOK,
+		"/rest/db/diff?folder=default&a=" + local:                 http.StatusBadRequest,
+	} {
+		r, _ := http.NewRequest("GET", query, nil)
+		w := httptest.NewRecorder()
+		if r.URL.Path == "/rest/db/diff" {
+			s.getDBDiff(w, r)
+		} else {
+			s.getDBRemoteNeed(w, r)
+		}
+		if w.Code != status {
+			t.Errorf("%s: status %d != expected %d", query, w.Code, status)
+		}
+	}
+}
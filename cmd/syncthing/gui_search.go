@@ -0,0 +1,110 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/model"
+)
+
+// getDBSearch finds the files in the global index of a folder by name, size,
+// modification time, deletion and availability. Deleted files are excluded
+// unless asked for with deleted=true or deleted=any.
+func (s *apiSvc) getDBSearch(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	folder := qs.Get("folder")
+
+	page, perpage := pagingParams(qs)
+
+	opts := model.SearchOptions{
+		Query:   qs.Get("q"),
+		Regex:   qs.Get("regex") != "",
+		Prefix:  qs.Get("prefix"),
+		Deleted: model.SearchExclude,
+	}
+	var err error
+	if opts.MinSize, err = parseSearchSize(qs.Get("minsize")); err != nil {
+		http.Error(w, "minsize: "+err.Error(), 400)
+		return
+	}
+	if opts.MaxSize, err = parseSearchSize(qs.Get("maxsize")); err != nil {
+		http.Error(w, "maxsize: "+err.Error(), 400)
+		return
+	}
+	if opts.ModifiedAfter, err = parseSearchTime(qs.Get("modifiedafter")); err != nil {
+		http.Error(w, "modifiedafter: "+err.Error(), 400)
+		return
+	}
+	if opts.ModifiedBefore, err = parseSearchTime(qs.Get("modifiedbefore")); err != nil {
+		http.Error(w, "modifiedbefore: "+err.Error(), 400)
+		return
+	}
+	if v := qs.Get("deleted"); v != "" {
+		if opts.Deleted, err = parseSearchFilter(v); err != nil {
+			http.Error(w, "deleted: "+err.Error(), 400)
+			return
+		}
+	}
+	if opts.Available, err = parseSearchFilter(qs.Get("available")); err != nil {
+		http.Error(w, "available: "+err.Error(), 400)
+		return
+	}
+
+	files, total, err := s.model.SearchGlobal(folder, opts, page, perpage)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	output := map[string]interface{}{
+		"files":   s.toNeedSlice(files),
+		"total":   total,
+		"page":    page,
+		"perpage": perpage,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(output)
+}
+
+func parseSearchSize(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// parseSearchTime accepts either an RFC 3339 timestamp or seconds since the
+// epoch.
+func parseSearchTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func parseSearchFilter(v string) (model.SearchFilter, error) {
+	if v == "" || v == "any" {
+		return model.SearchAny, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return model.SearchAny, err
+	}
+	if b {
+		return model.SearchOnly, nil
+	}
+	return model.SearchExclude, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestParseSearchParams(t *testing.T) {
+	for v, expected := range map[string]model.SearchFilter{
+		"":      model.SearchAny,
+		"any":   model.SearchAny,
+		"true":  model.SearchOnly,
+		"1":     model.SearchOnly,
+		"false": model.SearchExclude,
+	} {
+		if f, err := parseSearchFilter(v); err != nil || f != expected {
+			t.Errorf("filter %q: %v (%v) != expected %v", v, f, err, expected)
+		}
+	}
+	if _, err := parseSearchFilter("maybe"); err == nil {
+		t.Error("unexpected nil error for an invalid filter")
+	}
+
+	for v, expected := range map[string]time.Time{
+		"":                     {},
+		"1445000000":           time.Unix(1445000000, 0),
+		"2015-10-16T12:53:20Z": time.Unix(1445000000, 0),
+	} {
+		if tm, err := parseSearchTime(v); err != nil || !tm.Equal(expected) {
+			t.Errorf("time %q: %v (%v) != expected %v", v, tm, err, expected)
+		}
+	}
+	if _, err := parseSearchTime("yesterday"); err == nil {
+		t.Error("unexpected nil error for an invalid time")
+	}
+}
+
+func TestGetDBSearch(t *testing.T) {
+	fcfg := config.FolderConfiguration{
+		ID:      "default",
+		RawPath: "testdata",
+	}
+	cfg = config.Wrap("/tmp/test", config.Configuration{
+		Folders: []config.FolderConfiguration{fcfg},
+	})
+	m := model.NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+	s := &apiSvc{model: m}
+
+	for query, status := range map[string]int{
+		"folder=default&q=*.xml":                 http.StatusOK,
+		"folder=default&q=(&regex=true":          http.StatusBadRequest,
+		"folder=default&minsize=big":             http.StatusBadRequest,
+		"folder=default&deleted=maybe":           http.StatusBadRequest,
+		"folder=default&modifiedafter=yesterday": http.StatusBadRequest,
+		"folder=nonexistent":                     http.StatusBadRequest,
+	} {
+		r, _ := http.NewRequest("GET", "/rest/db/search?"+query, nil)
+		w := httptest.NewRecorder()
+		s.getDBSearch(w, r)
+		if w.Code != status {
+			t.Errorf("%s: status %d != expected %d", query, w.Code, status)
+		}
+	}
+
+	r, _ := http.NewRequest("GET", "/rest/db/search?folder=default&perpage=10", nil)
+	w := httptest.NewRecorder()
+	s.getDBSearch(w, r)
+	var res map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res["perpage"] != 10.0 || res["page"] != 1.0 || res["total"] != 0.0 {
+		t.Errorf("unexpected result %v", res)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+const (
+	// A session not used for this long must log in again. The GUI polls
+	// for events while open, so this is time since the GUI was closed.
+	sessionIdleTimeout = 24 * time.Hour
+	// A session must log in again this long after logging in.
+	sessionAbsoluteTimeout = 7 * 24 * time.Hour
+	// Session use is saved to the database at most this often.
+	sessionSaveInterval = time.Minute
+)
+
+const (
+	// The number of failed logins allowed from an address before it gets
+	// locked out.
+	loginFreeAttempts = 5
+	// The first lockout lasts this long; each further failure doubles it.
+	loginBaseLockout = 10 * time.Second
+	loginMaxLockout  = time.Hour
+	// Failures are forgotten when there have been none for this long.
+	loginFailureMemory = 24 * time.Hour
+)
+
+type guiSession struct {
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+func (s guiSession) expired(now time.Time) bool {
+	return now.Sub(s.LastUsed) > sessionIdleTimeout || now.Sub(s.Created) > sessionAbsoluteTimeout
+}
+
+// A sessionStore keeps track of the logged in GUI sessions, saving them in
+// the database, if there is one, so that they survive restarts.
+type sessionStore struct {
+	sessions map[string]guiSession
+	ns       *db.NamespacedKV
+	lastSave time.Time
+	mut      sync.Mutex
+}
+
+func newSessionStore(ldb *db.Instance) *sessionStore {
+	s := &sessionStore{
+		sessions: make(map[string]guiSession),
+		mut:      sync.NewMutex(),
+	}
+	if ldb == nil {
+		return s
+	}
+
+	s.ns = db.NewNamespacedKV(ldb, string([]byte{db.KeyTypeMiscData}))
+	if bs, ok := s.ns.Bytes("guiSessions"); ok {
+		if err := json.Unmarshal(bs, &s.sessions); err != nil {
+			l.Infoln("Loading GUI sessions:", err)
+		}
+	}
+	s.expire(time.Now())
+	return s
+}
+
+// create returns the ID of a new session.
+func (s *sessionStore) create() string {
+	id := randomString(32)
+	now := time.Now()
+
+	s.mut.Lock()
+	s.expire(now)
+	s.sessions[id] = guiSession{
+		Created:  now,
+		LastUsed: now,
+	}
+	s.save(now)
+	s.mut.Unlock()
+
+	return id
+}
+
+// use returns true if the session is valid, marking it as used.
+func (s *sessionStore) use(id string) bool {
+	now := time.Now()
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return false
+	}
+	if sess.expired(now) {
+		delete(s.sessions, id)
+		s.save(now)
+		return false
+	}
+
+	sess.LastUsed = now
+	s.sessions[id] = sess
+	if now.Sub(s.lastSave) > sessionSaveInterval {
+		s.save(now)
+	}
+	return true
+}
+
+func (s *sessionStore) remove(id string) {
+	s.mut.Lock()
+	if _, ok := s.sessions[id]; ok {
+		delete(s.sessions, id)
+		s.save(time.Now())
+	}
+	s.mut.Unlock()
+}
+
+// expire removes the expired sessions. Must be called with the lock held.
+func (s *sessionStore) expire(now time.Time) {
+	for id, sess := range s.sessions {
+		if sess.expired(now) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// save writes the sessions to the database. Must be called with the lock
+// held.
+func (s *sessionStore) save(now time.Time) {
+	s.lastSave = now
+	if s.ns == nil {
+		return
+	}
+	bs, err := json.Marshal(s.sessions)
+	if err != nil {
+		l.Infoln("Saving GUI sessions:", err)
+		return
+	}
+	s.ns.PutBytes("guiSessions", bs)
+}
+
+type loginFailures struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// A loginLimiter keeps track of failed login attempts per source address,
+// locking out addresses that fail too often for an exponentially growing
+// period.
+type loginLimiter struct {
+	failures map[string]*loginFailures
+	mut      sync.Mutex
+}
+
+func newLoginLimiter() *loginLimiter {
+	return &loginLimiter{
+		failures: make(map[string]*loginFailures),
+		mut:      sync.NewMutex(),
+	}
+}
+
+// lockedOut returns how long the address remains locked out, or zero.
+func (lim *loginLimiter) lockedOut(addr string) time.Duration {
+	lim.mut.Lock()
+	defer lim.mut.Unlock()
+
+	f, ok := lim.failures[addr]
+	if !ok {
+		return 0
+	}
+	if left := f.lockedUntil.Sub(time.Now()); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// failed records a failed login attempt from the address, locking it out if
+// there have been too many.
+func (lim *loginLimiter) failed(addr string) {
+	now := time.Now()
+
+	lim.mut.Lock()
+	defer lim.mut.Unlock()
+
+	for a, f := range lim.failures {
+		if now.Sub(f.last) > loginFailureMemory {
+			delete(lim.failures, a)
+		}
+	}
+
+	f, ok := lim.failures[addr]
+	if !ok {
+		f = &loginFailures{}
+		lim.failures[addr] = f
+	}
+	f.count++
+	f.last = now
+
+	if f.count < loginFreeAttempts {
+		return
+	}
+
+	lockout := loginMaxLockout
+	if shift := uint(f.count - loginFreeAttempts); shift < 16 {
+		if d := loginBaseLockout << shift; d < lockout {
+			lockout = d
+		}
+	}
+	f.lockedUntil = now.Add(lockout)
+
+	events.Default.Log(events.LoginLockout, map[string]interface{}{
+		"address":  addr,
+		"failures": f.count,
+		"until":    f.lockedUntil,
+	})
+}
+
+func (lim *loginLimiter) succeeded(addr string) {
+	lim.mut.Lock()
+	delete(lim.failures, addr)
+	lim.mut.Unlock()
+}
+
+// remoteHost returns the host part of the request's remote address.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (s *apiSvc) postSystemLogout(w http.ResponseWriter, r *http.Request) {
+	cookieName := s.sessionCookieName()
+	if cookie, err := r.Cookie(cookieName); err == nil {
+		s.sessions.remove(cookie.Value)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieName,
+		Value:  "",
+		Path:   "/",
+		MaxAge: -1,
+	})
+}
+
+func (s *apiSvc) sessionCookieName() string {
+	return "sessionid-" + s.id.String()[:5]
+}
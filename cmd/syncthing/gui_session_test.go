@@ -0,0 +1,189 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSessionExpiry(t *testing.T) {
+	s := newSessionStore(nil)
+
+	id := s.create()
+	if !s.use(id) {
+		t.Fatal("new session should be valid")
+	}
+	if s.use("bogus") {
+		t.Error("unknown session should not be valid")
+	}
+
+	now := time.Now()
+	s.sessions[id] = guiSession{
+		Created:  now.Add(-time.Hour),
+		LastUsed: now.Add(-sessionIdleTimeout - time.Minute),
+	}
+	if s.use(id) {
+		t.Error("idle session should have expired")
+	}
+	if _, ok := s.sessions[id]; ok {
+		t.Error("expired session should be removed")
+	}
+
+	id = s.create()
+	s.sessions[id] = guiSession{
+		Created:  now.Add(-sessionAbsoluteTimeout - time.Minute),
+		LastUsed: now,
+	}
+	if s.use(id) {
+		t.Error("old session should have expired")
+	}
+
+	id = s.create()
+	s.remove(id)
+	if s.use(id) {
+		t.Error("removed session should not be valid")
+	}
+}
+
+func TestSessionPersistence(t *testing.T) {
+	ldb := db.OpenMemory()
+
+	s := newSessionStore(ldb)
+	id1 := s.create()
+	id2 := s.create()
+	s.remove(id2)
+
+	s = newSessionStore(ldb)
+	if !s.use(id1) {
+		t.Error("session should survive a restart")
+	}
+	if s.use(id2) {
+		t.Error("removed session should not survive a restart")
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	sub := events.Default.Subscribe(events.LoginLockout)
+	defer events.Default.Unsubscribe(sub)
+
+	lim := newLoginLimiter()
+	for i := 0; i < loginFreeAttempts-1; i++ {
+		lim.failed("192.0.2.1")
+	}
+	if left := lim.lockedOut("192.0.2.1"); left != 0 {
+		t.Fatalf("locked out too early, for %v", left)
+	}
+
+	lim.failed("192.0.2.1")
+	first := lim.lockedOut("192.0.2.1")
+	if first <= 0 || first > loginBaseLockout {
+		t.Errorf("unexpected first lockout %v", first)
+	}
+	if ev, err := sub.Poll(time.Second); err != nil {
+		t.Error("expected a lockout event:", err)
+	} else if data := ev.Data.(map[string]interface{}); data["address"] != "192.0.2.1" {
+		t.Errorf("unexpected event data %v", data)
+	}
+
+	lim.failed("192.0.2.1")
+	if second := lim.lockedOut("192.0.2.1"); second <= loginBaseLockout {
+		t.Errorf("lockout should grow, got %v after %v", second, first)
+	}
+
+	for i := 0; i < 30; i++ {
+		lim.failed("192.0.2.1")
+	}
+	if left := lim.lockedOut("192.0.2.1"); left > loginMaxLockout {
+		t.Errorf("lockout %v exceeds the max", left)
+	}
+
+	if left := lim.lockedOut("192.0.2.2"); left != 0 {
+		t.Error("other addresses should not be locked out")
+	}
+
+	lim.succeeded("192.0.2.1")
+	if left := lim.lockedOut("192.0.2.1"); left != 0 {
+		t.Error("success should clear the lockout")
+	}
+}
+
+func TestBasicAuthLockout(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.GUIConfiguration{User: "user", Password: string(hash)}
+	noKey := func(*http.Request) bool { return false }
+	sessions := newSessionStore(nil)
+	handler := basicAuthAndSessionMiddleware("sessionid", cfg, noKey, sessions, newLoginLimiter(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(password string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		r.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("user:"+password)))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := request("pass")
+	if w.Code != http.StatusOK {
+		t.Fatalf("correct password gave status %d", w.Code)
+	}
+	if len(sessions.sessions) != 1 {
+		t.Errorf("expected one session, not %d", len(sessions.sessions))
+	}
+
+	for i := 0; i < loginFreeAttempts; i++ {
+		if w := request("wrong"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("wrong password gave status %d", w.Code)
+		}
+	}
+
+	w = request("pass")
+	if w.Code != 429 {
+		t.Errorf("locked out login gave status %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+}
+
+func TestLogout(t *testing.T) {
+	s := &apiSvc{
+		id:       protocol.LocalDeviceID,
+		sessions: newSessionStore(nil),
+	}
+	id := s.sessions.create()
+
+	r, _ := http.NewRequest("POST", "/rest/system/logout", nil)
+	r.AddCookie(&http.Cookie{Name: s.sessionCookieName(), Value: id})
+	w := httptest.NewRecorder()
+	s.restMux().ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout gave status %d", w.Code)
+	}
+	if s.sessions.use(id) {
+		t.Error("session should be removed on logout")
+	}
+	cookie := w.Header().Get("Set-Cookie")
+	if !strings.HasPrefix(cookie, s.sessionCookieName()+"=;") || !strings.Contains(cookie, "Max-Age=0") {
+		t.Errorf("session cookie should be cleared, got %q", cookie)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// An apiTokenSet holds the API key and the scoped API tokens. It is updated
+// when the tokens change, without restarting the GUI listener.
+type apiTokenSet struct {
+	tokens map[string]config.APIToken
+	mut    sync.RWMutex
+}
+
+func newAPITokenSet(apiKey string, tokens []config.APIToken) *apiTokenSet {
+	s := &apiTokenSet{
+		mut: sync.NewRWMutex(),
+	}
+	s.update(apiKey, tokens)
+	return s
+}
+
+func (s *apiTokenSet) update(apiKey string, tokens []config.APIToken) {
+	m := make(map[string]config.APIToken, len(tokens)+1)
+	for _, tok := range tokens {
+		if tok.Token != "" {
+			m[tok.Token] = tok
+		}
+	}
+	if apiKey != "" {
+		// The API key is a token allowing everything.
+		m[apiKey] = config.APIToken{
+			Name:  "apikey",
+			Token: apiKey,
+			Scope: config.ScopeAdmin,
+		}
+	}
+
+	s.mut.Lock()
+	s.tokens = m
+	s.mut.Unlock()
+}
+
+// lookup returns the token given in the X-API-Key header, or as a bearer
+// token in the Authorization header, if it is a valid one.
+func (s *apiTokenSet) lookup(r *http.Request) (config.APIToken, bool) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		if hdr := r.Header.Get("Authorization"); strings.HasPrefix(hdr, "Bearer ") {
+			key = hdr[7:]
+		}
+	}
+	if key == "" {
+		return config.APIToken{}, false
+	}
+
+	s.mut.RLock()
+	tok, ok := s.tokens[key]
+	s.mut.RUnlock()
+	return tok, ok
+}
+
+func (s *apiTokenSet) valid(r *http.Request) bool {
+	_, ok := s.lookup(r)
+	return ok
+}
+
+// The REST endpoints a read only token may not GET, as they expose secrets
+// or things outside of Syncthing.
+var restrictedGets = []string{
+	"/rest/system/config",
+	"/rest/system/browse",
+	"/rest/system/tokens",
+	"/rest/debug/",
+}
+
+// The REST endpoints a token limited to some folders may use without
+// naming a folder.
+var folderlessRequests = map[string]bool{
+	"GET /rest/system/ping":    true,
+	"GET /rest/system/status":  true,
+	"GET /rest/system/version": true,
+}
+
+// tokenPermits returns true if the request is within the scope of the token.
+func tokenPermits(tok config.APIToken, r *http.Request) bool {
+	if !strings.HasPrefix(r.URL.Path, "/rest/") {
+		return true
+	}
+
+	switch tok.Scope {
+	case config.ScopeAdmin:
+	case config.ScopeScan:
+		if r.Method != "GET" && r.URL.Path != "/rest/db/scan" {
+			return false
+		}
+	default:
+		if r.Method != "GET" {
+			return false
+		}
+	}
+	if tok.Scope != config.ScopeAdmin && r.Method == "GET" {
+		for _, prefix := range restrictedGets {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				return false
+			}
+		}
+	}
+
+	if len(tok.Folders) == 0 {
+		return true
+	}
+	if folderlessRequests[r.Method+" "+r.URL.Path] {
+		return true
+	}
+	folder, ok := requestFolder(r)
+	return ok && tok.AllowsFolder(folder)
+}
+
+// requestFolder returns the folder a request concerns, if it's to one of the
+// REST endpoints that only concern a single folder. Other requests may still
+// have a folder parameter, but aren't limited to that folder.
+func requestFolder(r *http.Request) (string, bool) {
+	p := r.URL.Path
+	if path.Clean(p) != p {
+		return "", false
+	}
+
+	var folder string
+	switch {
+	case strings.HasPrefix(p, "/rest/config/folders/"):
+		folder = strings.TrimPrefix(p, "/rest/config/folders/")
+		if strings.Contains(folder, "/") {
+			return "", false
+		}
+	case strings.HasPrefix(p, "/rest/db/"), r.Method+" "+p == "POST /rest/system/reset":
+		folder = r.URL.Query().Get("folder")
+	}
+	return folder, folder != ""
+}
+
+// tokenScopeMiddleware rejects requests made with an API token that does not
+// permit them. Requests without a token are left to the other middlewares.
+func tokenScopeMiddleware(tokens *apiTokenSet, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tok, ok := tokens.lookup(r); ok && !tokenPermits(tok, r) {
+			if debugHTTP {
+				l.Debugf("http: token %q not permitted to %s %s", tok.Name, r.Method, r.URL)
+			}
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiTokenInfo is a token as shown to API clients, without the secret.
+type apiTokenInfo struct {
+	Name    string               `json:"name"`
+	Scope   config.APITokenScope `json:"scope"`
+	Folders []string             `json:"folders"`
+}
+
+func (s *apiSvc) getSystemTokens(w http.ResponseWriter, r *http.Request) {
+	tokens := cfg.GUI().APITokens
+	res := make([]apiTokenInfo, len(tokens))
+	for i, tok := range tokens {
+		res[i] = apiTokenInfo{
+			Name:    tok.Name,
+			Scope:   tok.Scope,
+			Folders: tok.Folders,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(res)
+}
+
+// postSystemTokens creates a new token with the posted name, scope and
+// folders, and returns it. This is the only time the token itself is shown.
+func (s *apiSvc) postSystemTokens(w http.ResponseWriter, r *http.Request) {
+	var info apiTokenInfo
+	err := json.NewDecoder(r.Body).Decode(&info)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if info.Name == "" {
+		http.Error(w, "token name must not be empty", 400)
+		return
+	}
+
+	s.systemConfigMut.Lock()
+	defer s.systemConfigMut.Unlock()
+
+	gui := cfg.GUI()
+	for _, tok := range gui.APITokens {
+		if tok.Name == info.Name {
+			http.Error(w, fmt.Sprintf("token %q already exists", info.Name), 400)
+			return
+		}
+	}
+	folders := cfg.Folders()
+	for _, folder := range info.Folders {
+		if _, ok := folders[folder]; !ok {
+			http.Error(w, fmt.Sprintf("unknown folder %q", folder), 400)
+			return
+		}
+	}
+
+	tok := config.APIToken{
+		Name:    info.Name,
+		Token:   randomString(32),
+		Scope:   info.Scope,
+		Folders: info.Folders,
+	}
+	gui.APITokens = append(gui.APITokens, tok)
+	cfg.SetGUI(gui)
+	if err := cfg.SaveFrom(s.changeSource(r)); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(tok)
+}
+
+func (s *apiSvc) postSystemTokensRevoke(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	s.systemConfigMut.Lock()
+	defer s.systemConfigMut.Unlock()
+
+	gui := cfg.GUI()
+	for i, tok := range gui.APITokens {
+		if tok.Name != name {
+			continue
+		}
+		gui.APITokens = append(gui.APITokens[:i], gui.APITokens[i+1:]...)
+		cfg.SetGUI(gui)
+		if err := cfg.SaveFrom(s.changeSource(r)); err != nil {
+			http.Error(w, err.Error(), 500)
+		}
+		return
+	}
+
+	http.Error(w, fmt.Sprintf("no such token %q", name), 404)
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+func TestTokenPermits(t *testing.T) {
+	readOnly := config.APIToken{Scope: config.ScopeReadOnly}
+	scan := config.APIToken{Scope: config.ScopeScan}
+	admin := config.APIToken{Scope: config.ScopeAdmin}
+	scanDefault := config.APIToken{Scope: config.ScopeScan, Folders: []string{"default"}}
+	adminDefault := config.APIToken{Scope: config.ScopeAdmin, Folders: []string{"default"}}
+	readOnlyDefault := config.APIToken{Scope: config.ScopeReadOnly, Folders: []string{"default"}}
+
+	var tests = []struct {
+		tok    config.APIToken
+		method string
+		url    string
+		ok     bool
+	}{
+		{readOnly, "GET", "/rest/system/status", true},
+		{readOnly, "GET", "/rest/db/status?folder=other", true},
+		{readOnly, "GET", "/rest/system/config", false},
+		{readOnly, "GET", "/rest/system/tokens", false},
+		{readOnly, "GET", "/rest/debug/peerCompletion", false},
+		{readOnly, "POST", "/rest/db/scan?folder=default", false},
+		{readOnly, "GET", "/index.html", true},
+
+		{scan, "GET", "/rest/db/status?folder=default", true},
+		{scan, "POST", "/rest/db/scan?folder=default", true},
+		{scan, "POST", "/rest/system/restart", false},
+		{scan, "GET", "/rest/system/config", false},
+
+		{admin, "GET", "/rest/system/config", true},
+		{admin, "POST", "/rest/system/restart", true},
+
+		{scanDefault, "POST", "/rest/db/scan?folder=default", true},
+		{scanDefault, "POST", "/rest/db/scan?folder=other", false},
+		{scanDefault, "GET", "/rest/db/status?folder=other", false},
+		{scanDefault, "GET", "/rest/system/version", true},
+		{scanDefault, "GET", "/rest/events", false},
+
+		{adminDefault, "POST", "/rest/system/reset?folder=default", true},
+		{adminDefault, "POST", "/rest/system/reset", false},
+		{adminDefault, "GET", "/rest/system/config", false},
+		{adminDefault, "GET", "/rest/config/folders/default", true},
+		{adminDefault, "PATCH", "/rest/config/folders/default?folder=other", true},
+		{adminDefault, "DELETE", "/rest/config/folders/other", false},
+		{adminDefault, "GET", "/rest/config/folders", false},
+
+		// Naming an allowed folder doesn't open up other endpoints.
+		{adminDefault, "POST", "/rest/system/tokens?folder=default", false},
+		{adminDefault, "POST", "/rest/system/config?folder=default", false},
+		{adminDefault, "POST", "/rest/system/shutdown?folder=default", false},
+		{adminDefault, "DELETE", "/rest/config/folders/other?folder=default", false},
+		{adminDefault, "GET", "/rest/config/devices?folder=default", false},
+		{adminDefault, "GET", "/rest/db/../system/config?folder=default", false},
+		{readOnlyDefault, "GET", "/rest/config/folders/other?folder=default", false},
+		{readOnlyDefault, "GET", "/rest/system/connections?folder=default", false},
+		{readOnlyDefault, "GET", "/rest/db/status?folder=default", true},
+	}
+
+	for _, tc := range tests {
+		r, _ := http.NewRequest(tc.method, tc.url, nil)
+		if ok := tokenPermits(tc.tok, r); ok != tc.ok {
+			t.Errorf("%v %s %s: %v != expected %v", tc.tok, tc.method, tc.url, ok, tc.ok)
+		}
+	}
+}
+
+func TestTokenLookup(t *testing.T) {
+	tokens := newAPITokenSet("apikey", []config.APIToken{
+		{Name: "monitor", Token: "t1", Scope: config.ScopeReadOnly},
+		{Name: "empty"},
+	})
+
+	r, _ := http.NewRequest("GET", "/rest/system/status", nil)
+	if tokens.valid(r) {
+		t.Error("a request without token should not be valid")
+	}
+
+	r.Header.Set("X-API-Key", "t1")
+	if tok, ok := tokens.lookup(r); !ok || tok.Name != "monitor" {
+		t.Errorf("unexpected token %v for X-API-Key", tok)
+	}
+
+	r.Header.Del("X-API-Key")
+	r.Header.Set("Authorization", "Bearer apikey")
+	if tok, ok := tokens.lookup(r); !ok || tok.Scope != config.ScopeAdmin {
+		t.Errorf("the API key should be an admin token, not %v", tok)
+	}
+
+	tokens.update("apikey", nil)
+	r.Header.Set("Authorization", "Bearer t1")
+	if tokens.valid(r) {
+		t.Error("a revoked token should not be valid")
+	}
+}
+
+func TestTokenScopeMiddleware(t *testing.T) {
+	tokens := newAPITokenSet("", []config.APIToken{
+		{Name: "monitor", Token: "t1", Scope: config.ScopeReadOnly},
+	})
+	handler := tokenScopeMiddleware(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	var tests = []struct {
+		token  string
+		method string
+		status int
+	}{
+		{"t1", "GET", http.StatusOK},
+		{"t1", "POST", http.StatusForbidden},
+		// Requests without a valid token are left to the other middlewares
+		{"", "POST", http.StatusOK},
+		{"t2", "POST", http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		r, _ := http.NewRequest(tc.method, "/rest/system/ping", nil)
+		r.Header.Set("X-API-Key", tc.token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tc.status {
+			t.Errorf("%q %s: status %d != expected %d", tc.token, tc.method, w.Code, tc.status)
+		}
+	}
+}
@@ -120,7 +120,7 @@ var (
 )
 
 const (
-	usage      = "syncthing [options]"
+	usage      = "syncthing [options]\n  " + cliUsage
 	extraUsage = `
 The default configuration directory is:
 
@@ -217,6 +217,10 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cli" {
+		os.Exit(cliMain(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	if runtime.GOOS == "windows" {
 		// On Windows, we use a log file by default. Setting the -logfile flag
 		// to "-" disables this behavior.
@@ -596,9 +600,9 @@ func syncthingMain() {
 	}
 
 	dbFile := locations[locDatabase]
-	ldb, err := leveldb.OpenFile(dbFile, dbOpts())
+	rawDB, err := leveldb.OpenFile(dbFile, dbOpts())
 	if leveldbIsCorrupted(err) {
-		ldb, err = leveldb.RecoverFile(dbFile, dbOpts())
+		rawDB, err = leveldb.RecoverFile(dbFile, dbOpts())
 	}
 	if leveldbIsCorrupted(err) {
 		// The database is corrupted, and we've tried to recover it but it
@@ -608,11 +612,12 @@ func syncthingMain() {
 		if err := resetDB(); err != nil {
 			l.Fatalln("Remove database:", err)
 		}
-		ldb, err = leveldb.OpenFile(dbFile, dbOpts())
+		rawDB, err = leveldb.OpenFile(dbFile, dbOpts())
 	}
 	if err != nil {
 		l.Fatalln("Cannot open database:", err, "- Is another copy of Syncthing already running?")
 	}
+	ldb := db.NewInstance(rawDB)
 
 	// Remove database entries for folders that no longer exist in the config
 	folders := cfg.Folders()
@@ -740,7 +745,7 @@ func syncthingMain() {
 
 	// GUI
 
-	setupGUI(mainSvc, cfg, m, apiSub, cachedDiscovery, relaySvc)
+	setupGUI(mainSvc, cfg, ldb, m, apiSub, cachedDiscovery, relaySvc)
 
 	// Start connection management
 
@@ -867,7 +872,7 @@ func startAuditing(mainSvc *suture.Supervisor) {
 	l.Infoln("Audit log in", auditFile)
 }
 
-func setupGUI(mainSvc *suture.Supervisor, cfg *config.Wrapper, m *model.Model, apiSub *events.BufferedSubscription, discoverer *discover.CachingMux, relaySvc *relay.Svc) {
+func setupGUI(mainSvc *suture.Supervisor, cfg *config.Wrapper, ldb *db.Instance, m *model.Model, apiSub *events.BufferedSubscription, discoverer *discover.CachingMux, relaySvc *relay.Svc) {
 	opts := cfg.Options()
 	guiCfg := overrideGUIConfig(cfg.GUI(), guiAddress, guiAuthentication, guiAPIKey)
 
@@ -896,7 +901,7 @@ func setupGUI(mainSvc *suture.Supervisor, cfg *config.Wrapper, m *model.Model, a
 
 			urlShow := fmt.Sprintf("%s://%s/", proto, net.JoinHostPort(hostShow, strconv.Itoa(addr.Port)))
 			l.Infoln("Starting web GUI on", urlShow)
-			api, err := newAPISvc(myID, guiCfg, guiAssets, m, apiSub, discoverer, relaySvc)
+			api, err := newAPISvc(myID, guiCfg, guiAssets, ldb, m, apiSub, discoverer, relaySvc)
 			if err != nil {
 				l.Fatalln("Cannot start GUI:", err)
 			}
@@ -917,11 +922,12 @@ func defaultConfig(myName string) config.Configuration {
 	newCfg := config.New(myID)
 	newCfg.Folders = []config.FolderConfiguration{
 		{
-			ID:              "default",
-			RawPath:         locations[locDefFolder],
-			RescanIntervalS: 60,
-			MinDiskFreePct:  1,
-			Devices:         []config.FolderDeviceConfiguration{{DeviceID: myID}},
+			ID:                "default",
+			RawPath:           locations[locDefFolder],
+			RescanIntervalS:   60,
+			MinDiskFreePct:    1,
+			CaseInsensitiveFS: osutil.IsCaseInsensitive(locations[locDefFolder]),
+			Devices:           []config.FolderDeviceConfiguration{{DeviceID: myID}},
 		},
 	}
 	newCfg.Devices = []config.DeviceConfiguration{
@@ -0,0 +1,276 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/model"
+)
+
+// The folder states exported as syncthing_folder_state, so that each folder
+// has a series for every state, set to one for the current one.
+var metricsFolderStates = []string{"idle", "scanning", "scan-waiting", "syncing", "error"}
+
+// A metricsWriter writes metrics in the Prometheus text exposition format.
+type metricsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// metric starts a new metric family.
+func (m *metricsWriter) metric(name, kind, help string) {
+	m.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes a value of the metric, with the given label names and
+// values.
+func (m *metricsWriter) sample(name string, value float64, labels ...string) {
+	var buf []byte
+	buf = append(buf, name...)
+	if len(labels) > 0 {
+		buf = append(buf, '{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, labels[i]...)
+			buf = append(buf, `="`...)
+			buf = append(buf, metricsLabelEscaper.Replace(labels[i+1])...)
+			buf = append(buf, '"')
+		}
+		buf = append(buf, '}')
+	}
+	buf = append(buf, ' ')
+	buf = strconv.AppendFloat(buf, value, 'g', -1, 64)
+	buf = append(buf, '\n')
+	m.write(buf)
+}
+
+func (m *metricsWriter) printf(format string, args ...interface{}) {
+	m.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (m *metricsWriter) write(bs []byte) {
+	if m.err == nil {
+		_, m.err = m.w.Write(bs)
+	}
+}
+
+var metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// getMetrics serves the state of the folders, devices, relays and discovery
+// in a format suitable for scraping by Prometheus.
+func (s *apiSvc) getMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m := &metricsWriter{w: w}
+
+	s.writeFolderMetrics(m)
+	s.writeDeviceMetrics(m)
+	s.writeServiceMetrics(m)
+
+	if m.err != nil && debugHTTP {
+		l.Debugln("http: writing metrics:", m.err)
+	}
+}
+
+func (s *apiSvc) writeFolderMetrics(m *metricsWriter) {
+	var folders []string
+	for folder := range cfg.Folders() {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+
+	type sizes struct {
+		globalFiles, globalDeleted, localFiles, localDeleted, needFiles int
+		globalBytes, localBytes, needBytes                              int64
+	}
+	folderSizes := make([]sizes, len(folders))
+	for i, folder := range folders {
+		sz := &folderSizes[i]
+		sz.globalFiles, sz.globalDeleted, sz.globalBytes = s.model.GlobalSize(folder)
+		sz.localFiles, sz.localDeleted, sz.localBytes = s.model.LocalSize(folder)
+		sz.needFiles, sz.needBytes = s.model.NeedSize(folder)
+	}
+
+	gauges := []struct {
+		name, help string
+		value      func(sizes) float64
+	}{
+		{"syncthing_folder_global_files", "Number of files in the global version of the folder.", func(sz sizes) float64 { return float64(sz.globalFiles) }},
+		{"syncthing_folder_global_deleted", "Number of deleted files in the global version of the folder.", func(sz sizes) float64 { return float64(sz.globalDeleted) }},
+		{"syncthing_folder_global_bytes", "Size of the global version of the folder.", func(sz sizes) float64 { return float64(sz.globalBytes) }},
+		{"syncthing_folder_local_files", "Number of files in the local version of the folder.", func(sz sizes) float64 { return float64(sz.localFiles) }},
+		{"syncthing_folder_local_deleted", "Number of deleted files in the local version of the folder.", func(sz sizes) float64 { return float64(sz.localDeleted) }},
+		{"syncthing_folder_local_bytes", "Size of the local version of the folder.", func(sz sizes) float64 { return float64(sz.localBytes) }},
+		{"syncthing_folder_need_files", "Number of files needed to bring the folder up to date.", func(sz sizes) float64 { return float64(sz.needFiles) }},
+		{"syncthing_folder_need_bytes", "Number of bytes needed to bring the folder up to date.", func(sz sizes) float64 { return float64(sz.needBytes) }},
+	}
+	for _, g := range gauges {
+		m.metric(g.name, "gauge", g.help)
+		for i, folder := range folders {
+			m.sample(g.name, g.value(folderSizes[i]), "folder", folder)
+		}
+	}
+
+	m.metric("syncthing_folder_state", "gauge", "Whether the folder is in the given state.")
+	for _, folder := range folders {
+		cur, _, _ := s.model.State(folder)
+		for _, state := range metricsFolderStates {
+			m.sample("syncthing_folder_state", boolMetric(cur == state), "folder", folder, "state", state)
+		}
+	}
+
+	totals := make([]map[string]model.StateTotal, len(folders))
+	for i, folder := range folders {
+		totals[i] = s.model.StateTotals(folder)
+	}
+	counters := []struct {
+		name, help, state string
+		seconds           bool
+	}{
+		{"syncthing_folder_scans_total", "Number of completed scans of the folder.", "scanning", false},
+		{"syncthing_folder_scan_seconds_total", "Time spent on completed scans of the folder.", "scanning", true},
+		{"syncthing_folder_pulls_total", "Number of completed pulls of the folder.", "syncing", false},
+		{"syncthing_folder_pull_seconds_total", "Time spent on completed pulls of the folder.", "syncing", true},
+	}
+	for _, c := range counters {
+		m.metric(c.name, "counter", c.help)
+		for i, folder := range folders {
+			total := totals[i][c.state]
+			value := float64(total.Count)
+			if c.seconds {
+				value = total.Duration.Seconds()
+			}
+			m.sample(c.name, value, "folder", folder)
+		}
+	}
+}
+
+func (s *apiSvc) writeDeviceMetrics(m *metricsWriter) {
+	stats := s.model.ConnectionStats()
+	conns, _ := stats["connections"].(map[string]model.ConnectionInfo)
+	devices := cfg.Devices()
+
+	var ids []string
+	for id := range conns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	deviceNames := make(map[string]string, len(devices))
+	for id, dev := range devices {
+		deviceNames[id.String()] = dev.Name
+	}
+
+	gauges := []struct {
+		name, kind, help string
+		value            func(model.ConnectionInfo) float64
+	}{
+		{"syncthing_device_connected", "gauge", "Whether the device is connected.", func(ci model.ConnectionInfo) float64 { return boolMetric(ci.Connected) }},
+		{"syncthing_device_paused", "gauge", "Whether the device is paused.", func(ci model.ConnectionInfo) float64 { return boolMetric(ci.Paused) }},
+		{"syncthing_device_in_bytes_total", "counter", "Bytes received from the device over the current connection.", func(ci model.ConnectionInfo) float64 { return float64(ci.InBytesTotal) }},
+		{"syncthing_device_out_bytes_total", "counter", "Bytes sent to the device over the current connection.", func(ci model.ConnectionInfo) float64 { return float64(ci.OutBytesTotal) }},
+	}
+	for _, g := range gauges {
+		m.metric(g.name, g.kind, g.help)
+		for _, id := range ids {
+			m.sample(g.name, g.value(conns[id]), "device", id, "name", deviceNames[id])
+		}
+	}
+
+	if total, ok := stats["total"].(model.ConnectionInfo); ok {
+		m.metric("syncthing_in_bytes_total", "counter", "Bytes received from all devices.")
+		m.sample("syncthing_in_bytes_total", float64(total.InBytesTotal))
+		m.metric("syncthing_out_bytes_total", "counter", "Bytes sent to all devices.")
+		m.sample("syncthing_out_bytes_total", float64(total.OutBytesTotal))
+	}
+}
+
+func (s *apiSvc) writeServiceMetrics(m *metricsWriter) {
+	if s.relaySvc != nil {
+		relays := s.relaySvc.Relays()
+		sort.Strings(relays)
+		latencies := make([]time.Duration, len(relays))
+
+		m.metric("syncthing_relay_connected", "gauge", "Whether the relay client is connected to the relay.")
+		for i, relay := range relays {
+			latency, ok := s.relaySvc.RelayStatus(relay)
+			latencies[i] = latency
+			m.sample("syncthing_relay_connected", boolMetric(ok), "relay", relay)
+		}
+		m.metric("syncthing_relay_latency_seconds", "gauge", "Latency to the relay.")
+		for i, relay := range relays {
+			m.sample("syncthing_relay_latency_seconds", latencies[i].Seconds(), "relay", relay)
+		}
+	}
+
+	if s.discoverer != nil {
+		children := s.discoverer.ChildErrors()
+		var methods []string
+		for method := range children {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		m.metric("syncthing_discovery_ok", "gauge", "Whether the discovery method works.")
+		for _, method := range methods {
+			m.sample("syncthing_discovery_ok", boolMetric(children[method] == nil), "method", method)
+		}
+	}
+
+	if size, err := dirSize(locations[locDatabase]); err == nil {
+		m.metric("syncthing_database_bytes", "gauge", "Size of the database on disk.")
+		m.sample("syncthing_database_bytes", float64(size))
+	}
+
+	m.metric("syncthing_uptime_seconds", "gauge", "Time since Syncthing started.")
+	m.sample("syncthing_uptime_seconds", time.Since(startTime).Seconds())
+}
+
+// dirSize returns the total size of the files in the directory.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// metricsMiddleware serves the metrics endpoint directly, bypassing the
+// authentication of the other handlers, when it is configured not to
+// require authentication.
+func metricsMiddleware(metrics http.Handler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			metrics.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestMetricsWriter(t *testing.T) {
+	var buf bytes.Buffer
+	m := &metricsWriter{w: &buf}
+
+	m.metric("test_bytes", "gauge", "Some bytes.")
+	m.sample("test_bytes", 1.5)
+	m.sample("test_bytes", 1e12, "folder", `a "quoted"\ name`, "state", "idle")
+
+	expected := `# HELP test_bytes Some bytes.
+# TYPE test_bytes gauge
+test_bytes 1.5
+test_bytes{folder="a \"quoted\"\\ name",state="idle"} 1e+12
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	device2, _ := protocol.DeviceIDFromString("GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY")
+	fcfg := config.FolderConfiguration{
+		ID:      "default",
+		RawPath: "testdata",
+	}
+	cfg = config.Wrap("/tmp/test", config.Configuration{
+		Folders: []config.FolderConfiguration{fcfg},
+		Devices: []config.DeviceConfiguration{
+			{DeviceID: device2, Name: "other"},
+		},
+	})
+	m := model.NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+
+	s := &apiSvc{model: m}
+	r, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	s.getMetrics(w, r)
+
+	out := w.Body.String()
+	for _, line := range []string{
+		`syncthing_folder_need_bytes{folder="default"} 0`,
+		`syncthing_folder_state{folder="default",state="idle"} 0`, // not started
+		`syncthing_folder_scans_total{folder="default"} 0`,
+		`syncthing_device_connected{device="` + device2.String() + `",name="other"} 0`,
+		`# TYPE syncthing_in_bytes_total counter`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in output:\n%s", line, out)
+		}
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	denied := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Not Authorized", http.StatusUnauthorized)
+	})
+	handler := metricsMiddleware(metrics, denied)
+
+	for path, status := range map[string]int{
+		"/metrics":            http.StatusOK,
+		"/rest/system/status": http.StatusUnauthorized,
+		"/metrics/other":      http.StatusUnauthorized,
+	} {
+		r, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Errorf("%s: status %d != expected %d", path, w.Code, status)
+		}
+	}
+}
@@ -85,6 +85,7 @@ func (cfg Configuration) Copy() Configuration {
 	}
 
 	newCfg.Options = cfg.Options.Copy()
+	newCfg.GUI = cfg.GUI.Copy()
 
 	// DeviceIDs are values
 	newCfg.IgnoredDevices = make([]protocol.DeviceID, len(cfg.IgnoredDevices))
@@ -108,7 +109,17 @@ type FolderConfiguration struct {
 	Hashers               int                         `xml:"hashers" json:"hashers"` // Less than one sets the value to the number of cores. These are CPU bound due to hashing.
 	Order                 PullOrder                   `xml:"order" json:"order"`
 	IgnoreDelete          bool                        `xml:"ignoreDelete" json:"ignoreDelete"`
-	ScanProgressIntervalS int                         `xml:"scanProgressInterval" json:"scanProgressInterval"` // Set to a negative value to disable. Value of 0 will get replaced with value of 2 (default value)
+	ScanProgressIntervalS int                         `xml:"scanProgressInterval" json:"scanProgressInterval"`   // Set to a negative value to disable. Value of 0 will get replaced with value of 2 (default value)
+	ScrubIntervalH        int                         `xml:"scrubIntervalH" json:"scrubIntervalH"`               // How often to verify file contents against the index. Zero disables scrubbing.
+	ScrubRateKiBps        int                         `xml:"scrubRateKiBps" json:"scrubRateKiBps"`               // Maximum read rate while scrubbing. Zero is unlimited.
+	ScrubRepair           bool                        `xml:"scrubRepair" json:"scrubRepair"`                     // Fetch corrupted blocks from devices with the same version of the file.
+	HashAlgorithm         protocol.HashAlgorithm      `xml:"hashAlgorithm" json:"hashAlgorithm"`                 // Used for new and changed files. All devices sharing the folder must support it.
+	MaxHashMBps           int                         `xml:"maxHashMBps" json:"maxHashMBps"`                     // Hashing rate limit for this folder. Zero is unlimited.
+	CaseInsensitiveFS     bool                        `xml:"caseInsensitiveFS" json:"caseInsensitiveFS"`         // Detected when the folder is added. Names differing only in case are then treated as conflicts when pulling.
+	TargetPlatforms       []string                    `xml:"targetPlatform" json:"targetPlatforms"`              // File names unusable on any of these ("windows", "darwin", "linux") are not synced.
+	IgnoreLargerThanMiB   int64                       `xml:"ignoreLargerThanMiB" json:"ignoreLargerThanMiB"`     // Files larger than this are not synced. Zero is unlimited.
+	IgnoreModifiedWithinS int                         `xml:"ignoreModifiedWithinS" json:"ignoreModifiedWithinS"` // Files modified less than this long ago are not synced until they have been left alone.
+	Subscriptions         []string                    `xml:"subscription" json:"subscriptions"`                  // Only these subtrees are pulled. Empty means the whole folder.
 
 	Invalid string `xml:"-" json:"invalid"` // Set at runtime when there is an error, not saved
 }
@@ -274,6 +285,9 @@ type OptionsConfiguration struct {
 	MinHomeDiskFreePct      float64  `xml:"minHomeDiskFreePct" json:"minHomeDiskFreePct" default:"1"`
 	ReleasesURL             string   `xml:"releasesURL" json:"releasesURL" default:"https://api.github.com/repos/syncthing/syncthing/releases?per_page=30"`
 	AlwaysLocalNets         []string `xml:"alwaysLocalNet" json:"alwaysLocalNets"`
+	MaxHashMBps             int      `xml:"maxHashMBps" json:"maxHashMBps"`                      // Total hashing rate across all folders; 0 for unlimited
+	MaxConcurrentScans      int      `xml:"maxConcurrentScans" json:"maxConcurrentScans"`        // 0 for unlimited
+	ConfigRevisions         int      `xml:"configRevisions" json:"configRevisions" default:"25"` // Saved configurations kept in the history; 0 to keep none
 }
 
 func (orig OptionsConfiguration) Copy() OptionsConfiguration {
@@ -286,12 +300,51 @@ func (orig OptionsConfiguration) Copy() OptionsConfiguration {
 }
 
 type GUIConfiguration struct {
-	Enabled  bool   `xml:"enabled,attr" json:"enabled" default:"true"`
-	Address  string `xml:"address" json:"address" default:"127.0.0.1:8384"`
-	User     string `xml:"user,omitempty" json:"user"`
-	Password string `xml:"password,omitempty" json:"password"`
-	UseTLS   bool   `xml:"tls,attr" json:"useTLS"`
-	APIKey   string `xml:"apikey,omitempty" json:"apiKey"`
+	Enabled     bool       `xml:"enabled,attr" json:"enabled" default:"true"`
+	Address     string     `xml:"address" json:"address" default:"127.0.0.1:8384"`
+	User        string     `xml:"user,omitempty" json:"user"`
+	Password    string     `xml:"password,omitempty" json:"password"`
+	UseTLS      bool       `xml:"tls,attr" json:"useTLS"`
+	APIKey      string     `xml:"apikey,omitempty" json:"apiKey"`
+	APITokens   []APIToken `xml:"apiToken" json:"apiTokens"`
+	MetricsAuth bool       `xml:"metricsAuth,attr" json:"metricsAuth" default:"true"` // Whether /metrics requires authentication like the rest of the GUI
+}
+
+func (orig GUIConfiguration) Copy() GUIConfiguration {
+	c := orig
+	c.APITokens = nil
+	for _, tok := range orig.APITokens {
+		c.APITokens = append(c.APITokens, tok.Copy())
+	}
+	return c
+}
+
+// An APIToken grants access to the REST API, like the API key, but limited
+// to the given scope and, if any are listed, to the given folders.
+type APIToken struct {
+	Name    string        `xml:"name,attr" json:"name"`
+	Token   string        `xml:"token,attr" json:"token"`
+	Scope   APITokenScope `xml:"scope,attr" json:"scope"`
+	Folders []string      `xml:"folder" json:"folders"`
+}
+
+func (t APIToken) Copy() APIToken {
+	c := t
+	c.Folders = append([]string(nil), t.Folders...)
+	return c
+}
+
+// AllowsFolder returns true if the token may be used for the given folder.
+func (t APIToken) AllowsFolder(folder string) bool {
+	if len(t.Folders) == 0 {
+		return true
+	}
+	for _, f := range t.Folders {
+		if f == folder {
+			return true
+		}
+	}
+	return false
 }
 
 func New(myID protocol.DeviceID) Configuration {
@@ -464,6 +517,13 @@ func (cfg *Configuration) prepare(myID protocol.DeviceID) {
 	if cfg.GUI.APIKey == "" {
 		cfg.GUI.APIKey = randomString(32)
 	}
+
+	// API tokens added by hand may leave generating the token to us
+	for i := range cfg.GUI.APITokens {
+		if cfg.GUI.APITokens[i].Token == "" {
+			cfg.GUI.APITokens[i].Token = randomString(32)
+		}
+	}
 }
 
 // ChangeRequiresRestart returns true if updating the configuration requires a
@@ -826,3 +886,42 @@ func (o *PullOrder) UnmarshalText(bs []byte) error {
 	}
 	return nil
 }
+
+type APITokenScope int
+
+const (
+	ScopeReadOnly APITokenScope = iota // default is read only
+	ScopeScan
+	ScopeAdmin
+)
+
+func (s APITokenScope) String() string {
+	switch s {
+	case ScopeReadOnly:
+		return "readOnly"
+	case ScopeScan:
+		return "scan"
+	case ScopeAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+func (s APITokenScope) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *APITokenScope) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "readOnly":
+		*s = ScopeReadOnly
+	case "scan":
+		*s = ScopeScan
+	case "admin":
+		*s = ScopeAdmin
+	default:
+		*s = ScopeReadOnly
+	}
+	return nil
+}
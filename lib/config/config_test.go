@@ -62,6 +62,7 @@ func TestDefaultValues(t *testing.T) {
 		URInitialDelayS:         1800,
 		URPostInsecurely:        false,
 		ReleasesURL:             "https://api.github.com/repos/syncthing/syncthing/releases?per_page=30",
+		ConfigRevisions:         25,
 	}
 
 	cfg := New(device1)
@@ -179,6 +180,9 @@ func TestOverriddenValues(t *testing.T) {
 		URInitialDelayS:         800,
 		URPostInsecurely:        true,
 		ReleasesURL:             "https://localhost/releases",
+		MaxHashMBps:             25,
+		MaxConcurrentScans:      2,
+		ConfigRevisions:         5,
 	}
 
 	cfg, err := Load("testdata/overridenvalues.xml", device1)
@@ -407,6 +411,7 @@ func TestFolderPath(t *testing.T) {
 func TestNewSaveLoad(t *testing.T) {
 	path := "testdata/temp.xml"
 	os.Remove(path)
+	defer os.RemoveAll("testdata/config-history")
 
 	exists := func(path string) bool {
 		_, err := os.Stat(path)
@@ -621,6 +626,45 @@ func TestPullOrder(t *testing.T) {
 	}
 }
 
+func TestAPITokens(t *testing.T) {
+	wrapper, err := Load("testdata/apitokens.xml", device1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(tokens []APIToken) {
+		if len(tokens) != 3 {
+			t.Fatalf("Incorrect number of tokens, %d != 3", len(tokens))
+		}
+		if tokens[0].Name != "monitor" || tokens[0].Token != "abc123" || tokens[0].Scope != ScopeReadOnly {
+			t.Errorf("Incorrect default token %+v", tokens[0])
+		}
+		if tokens[1].Scope != ScopeScan || !tokens[1].AllowsFolder("default") || tokens[1].AllowsFolder("other") {
+			t.Errorf("Incorrect folder token %+v", tokens[1])
+		}
+		if tokens[2].Scope != ScopeAdmin || len(tokens[2].Token) == 0 || !tokens[2].AllowsFolder("other") {
+			t.Errorf("Incorrect generated token %+v", tokens[2])
+		}
+	}
+
+	check(wrapper.GUI().APITokens)
+
+	// Serialize and deserialize again to verify it survives the transformation
+
+	buf := new(bytes.Buffer)
+	cfg := wrapper.Raw()
+	cfg.WriteXML(buf)
+
+	cfg, err = ReadXML(buf, device1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GUI.APITokens[2].Token != wrapper.GUI().APITokens[2].Token {
+		t.Error("Generated token should survive serialization")
+	}
+	check(cfg.GUI.APITokens)
+}
+
 func TestLargeRescanInterval(t *testing.T) {
 	wrapper, err := Load("testdata/largeinterval.xml", device1)
 	if err != nil {
@@ -634,3 +678,31 @@ func TestLargeRescanInterval(t *testing.T) {
 		t.Error("negative rescan interval should become zero")
 	}
 }
+
+func TestRemoveFolderAndDevice(t *testing.T) {
+	wrapper, err := Load("testdata/example.xml", device1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapper.RemoveDevice(device4)
+	if _, ok := wrapper.Devices()[device4]; ok {
+		t.Error("device should have been removed")
+	}
+	if _, ok := wrapper.Devices()[device2]; !ok {
+		t.Error("other devices should remain")
+	}
+	for _, dev := range wrapper.Folders()["default"].Devices {
+		if dev.DeviceID == device4 {
+			t.Error("device should have been removed from the folder")
+		}
+	}
+	if len(wrapper.Folders()["default"].Devices) == 0 {
+		t.Error("other folder devices should remain")
+	}
+
+	wrapper.RemoveFolder("default")
+	if _, ok := wrapper.Folders()["default"]; ok {
+		t.Error("folder should have been removed")
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The kinds of sources of a configuration change.
+const (
+	SourceSyncthing  = "syncthing"  // Syncthing itself, such as on startup
+	SourceFile       = "file"       // The config file was edited by hand
+	SourceGUI        = "gui"        // A user logged in to the GUI
+	SourceToken      = "token"      // A REST API client, with the named API token
+	SourceCLI        = "cli"        // The syncthing cli command, with the named API token
+	SourceIntroducer = "introducer" // The named introducer device
+)
+
+// A ChangeSource describes who made a configuration change.
+type ChangeSource struct {
+	Type   string `json:"type"`
+	Name   string `json:"name,omitempty"`   // The API token or introducer
+	Reason string `json:"reason,omitempty"` // Such as the rolled back to revision
+}
+
+// A Revision is a configuration that was saved, as kept in the history.
+type Revision struct {
+	ID     int          `json:"id"`
+	Time   time.Time    `json:"time"`
+	Source ChangeSource `json:"source"`
+}
+
+// HistoryDir returns the directory where the configuration history is kept,
+// next to the config file. Each revision is a complete config file, named
+// after its ID.
+func (w *Wrapper) HistoryDir() string {
+	return filepath.Join(filepath.Dir(w.path), "config-history")
+}
+
+// Revisions returns the revisions in the configuration history, oldest
+// first.
+func (w *Wrapper) Revisions() ([]Revision, error) {
+	w.histMut.Lock()
+	defer w.histMut.Unlock()
+	return w.readRevisions()
+}
+
+// LoadRevision returns the configuration saved as the given revision.
+func (w *Wrapper) LoadRevision(id int, myID protocol.DeviceID) (Configuration, error) {
+	w.histMut.Lock()
+	defer w.histMut.Unlock()
+
+	fd, err := os.Open(w.revisionPath(id))
+	if os.IsNotExist(err) {
+		return Configuration{}, fmt.Errorf("no such revision %d", id)
+	} else if err != nil {
+		return Configuration{}, err
+	}
+	defer fd.Close()
+	return ReadXML(fd, myID)
+}
+
+func (w *Wrapper) revisionPath(id int) string {
+	return filepath.Join(w.HistoryDir(), strconv.Itoa(id)+".xml")
+}
+
+func (w *Wrapper) readRevisions() ([]Revision, error) {
+	bs, err := ioutil.ReadFile(filepath.Join(w.HistoryDir(), "index.json"))
+	if os.IsNotExist(err) {
+		return []Revision{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var revs []Revision
+	err = json.Unmarshal(bs, &revs)
+	return revs, err
+}
+
+// recordRevision adds the saved config file to the history, dropping the
+// oldest revisions beyond the configured count. The previous contents of the
+// config file are added first when they are not the latest revision, so that
+// a change made by hand can also be rolled back to.
+func (w *Wrapper) recordRevision(prev []byte, prevTime time.Time, cur []byte, src ChangeSource) error {
+	keep := w.cfg.Options.ConfigRevisions
+	if keep <= 0 {
+		return nil
+	}
+
+	revs, err := w.readRevisions()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(w.HistoryDir(), 0700); err != nil {
+		return err
+	}
+
+	var latest []byte
+	if len(revs) > 0 {
+		// A revision that has gone missing is the same as none.
+		latest, _ = ioutil.ReadFile(w.revisionPath(revs[len(revs)-1].ID))
+	}
+
+	add := func(bs []byte, t time.Time, src ChangeSource) error {
+		id := 1
+		if len(revs) > 0 {
+			id = revs[len(revs)-1].ID + 1
+		}
+		if err := writeFileAtomic(w.revisionPath(id), bs); err != nil {
+			return err
+		}
+		revs = append(revs, Revision{ID: id, Time: t, Source: src})
+		latest = bs
+		return nil
+	}
+
+	if prev != nil && !bytes.Equal(prev, latest) {
+		if err := add(prev, prevTime, ChangeSource{Type: SourceFile}); err != nil {
+			return err
+		}
+	}
+	if bytes.Equal(cur, latest) {
+		// Saved without a change.
+		return nil
+	}
+	if err := add(cur, time.Now(), src); err != nil {
+		return err
+	}
+
+	for len(revs) > keep {
+		os.Remove(w.revisionPath(revs[0].ID))
+		revs = revs[1:]
+	}
+
+	bs, err := json.MarshalIndent(revs, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(w.HistoryDir(), "index.json"), bs)
+}
+
+func writeFileAtomic(path string, bs []byte) error {
+	fd, err := osutil.CreateAtomic(path, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(bs); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syncthing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := New(device1)
+	cfg.Options.ConfigRevisions = 3
+	w := Wrap(filepath.Join(dir, "config.xml"), cfg)
+
+	setName := func(name string) {
+		w.SetDevice(DeviceConfiguration{DeviceID: device1, Name: name})
+	}
+
+	setName("one")
+	if err := w.SaveFrom(ChangeSource{Type: SourceGUI}); err != nil {
+		t.Fatal(err)
+	}
+	// Saving again without a change adds no revision.
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A change made by hand is recorded before the next save.
+	bs, _ := ioutil.ReadFile(w.ConfigPath())
+	bs = []byte(strings.Replace(string(bs), `name="one"`, `name="by hand"`, 1))
+	if err := ioutil.WriteFile(w.ConfigPath(), bs, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	setName("two")
+	if err := w.SaveFrom(ChangeSource{Type: SourceToken, Name: "deploy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := w.Revisions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ChangeSource{{Type: SourceGUI}, {Type: SourceFile}, {Type: SourceToken, Name: "deploy"}}
+	if len(revs) != len(expected) {
+		t.Fatalf("%d revisions != expected %d", len(revs), len(expected))
+	}
+	for i, rev := range revs {
+		if rev.ID != i+1 || rev.Source != expected[i] {
+			t.Errorf("unexpected revision %+v", rev)
+		}
+	}
+
+	old, err := w.LoadRevision(2, device1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := old.Devices[0].Name; name != "by hand" {
+		t.Errorf("revision 2 has device name %q", name)
+	}
+
+	// Only the configured number of revisions are kept.
+	setName("three")
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	revs, _ = w.Revisions()
+	if len(revs) != 3 || revs[0].ID != 2 || revs[2].ID != 4 {
+		t.Errorf("unexpected revisions after dropping the oldest, %+v", revs)
+	}
+	if _, err := w.LoadRevision(1, device1); err == nil {
+		t.Error("dropped revision should be gone")
+	}
+}
+
+func TestConfigHistoryDisabled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syncthing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := New(device1)
+	cfg.Options.ConfigRevisions = 0
+	w := Wrap(filepath.Join(dir, "config.xml"), cfg)
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(w.HistoryDir()); !os.IsNotExist(err) {
+		t.Error("history should not be kept")
+	}
+	if revs, err := w.Revisions(); err != nil || len(revs) != 0 {
+		t.Errorf("unexpected revisions %v (%v)", revs, err)
+	}
+}
@@ -7,7 +7,10 @@
 package config
 
 import (
+	"bytes"
+	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/osutil"
@@ -62,15 +65,17 @@ type Wrapper struct {
 	replaces  chan Configuration
 	subs      []Committer
 	mut       sync.Mutex
+	histMut   sync.Mutex // serializes saves, and access to the history
 }
 
 // Wrap wraps an existing Configuration structure and ties it to a file on
 // disk.
 func Wrap(path string, cfg Configuration) *Wrapper {
 	w := &Wrapper{
-		cfg:  cfg,
-		path: path,
-		mut:  sync.NewMutex(),
+		cfg:     cfg,
+		path:    path,
+		mut:     sync.NewMutex(),
+		histMut: sync.NewMutex(),
 	}
 	w.replaces = make(chan Configuration)
 	return w
@@ -214,6 +219,32 @@ func (w *Wrapper) SetDevice(dev DeviceConfiguration) CommitResponse {
 	return w.replaceLocked(newCfg)
 }
 
+// RemoveDevice removes the device from the configuration, and from all
+// folders shared with it.
+func (w *Wrapper) RemoveDevice(id protocol.DeviceID) CommitResponse {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+
+	newCfg := w.cfg.Copy()
+	for i := range newCfg.Devices {
+		if newCfg.Devices[i].DeviceID == id {
+			newCfg.Devices = append(newCfg.Devices[:i], newCfg.Devices[i+1:]...)
+			break
+		}
+	}
+	for i := range newCfg.Folders {
+		devs := newCfg.Folders[i].Devices[:0]
+		for _, dev := range newCfg.Folders[i].Devices {
+			if dev.DeviceID != id {
+				devs = append(devs, dev)
+			}
+		}
+		newCfg.Folders[i].Devices = devs
+	}
+
+	return w.replaceLocked(newCfg)
+}
+
 // Folders returns a map of folders. Folder structures should not be changed,
 // other than for the purpose of updating via SetFolder().
 func (w *Wrapper) Folders() map[string]FolderConfiguration {
@@ -250,6 +281,22 @@ func (w *Wrapper) SetFolder(fld FolderConfiguration) CommitResponse {
 	return w.replaceLocked(newCfg)
 }
 
+// RemoveFolder removes the folder with the given ID from the configuration.
+func (w *Wrapper) RemoveFolder(id string) CommitResponse {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+
+	newCfg := w.cfg.Copy()
+	for i := range newCfg.Folders {
+		if newCfg.Folders[i].ID == id {
+			newCfg.Folders = append(newCfg.Folders[:i], newCfg.Folders[i+1:]...)
+			break
+		}
+	}
+
+	return w.replaceLocked(newCfg)
+}
+
 // Options returns the current options configuration object.
 func (w *Wrapper) Options() OptionsConfiguration {
 	w.mut.Lock()
@@ -297,12 +344,32 @@ func (w *Wrapper) IgnoredDevice(id protocol.DeviceID) bool {
 
 // Save writes the configuration to disk, and generates a ConfigSaved event.
 func (w *Wrapper) Save() error {
+	return w.SaveFrom(ChangeSource{Type: SourceSyncthing})
+}
+
+// SaveFrom is like Save, and records the given source of the change with the
+// revision kept in the configuration history.
+func (w *Wrapper) SaveFrom(src ChangeSource) error {
+	w.histMut.Lock()
+	defer w.histMut.Unlock()
+
+	var buf bytes.Buffer
+	if err := w.cfg.WriteXML(&buf); err != nil {
+		return err
+	}
+
+	var prevTime time.Time
+	if info, err := os.Stat(w.path); err == nil {
+		prevTime = info.ModTime()
+	}
+	prev, _ := ioutil.ReadFile(w.path)
+
 	fd, err := osutil.CreateAtomic(w.path, 0600)
 	if err != nil {
 		return err
 	}
 
-	if err := w.cfg.WriteXML(fd); err != nil {
+	if _, err := fd.Write(buf.Bytes()); err != nil {
 		fd.Close()
 		return err
 	}
@@ -311,6 +378,10 @@ func (w *Wrapper) Save() error {
 		return err
 	}
 
+	if err := w.recordRevision(prev, prevTime, buf.Bytes(), src); err != nil {
+		l.Warnln("Recording configuration revision:", err)
+	}
+
 	events.Default.Log(events.ConfigSaved, w.cfg)
 	return nil
 }
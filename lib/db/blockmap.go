@@ -13,7 +13,6 @@
 package db
 
 import (
-	"bytes"
 	"encoding/binary"
 	"fmt"
 
@@ -27,11 +26,11 @@ import (
 var blockFinder *BlockFinder
 
 type BlockMap struct {
-	db     *leveldb.DB
+	db     *Instance
 	folder string
 }
 
-func NewBlockMap(db *leveldb.DB, folder string) *BlockMap {
+func NewBlockMap(db *Instance, folder string) *BlockMap {
 	return &BlockMap{
 		db:     db,
 		folder: folder,
@@ -93,7 +92,7 @@ func (m *BlockMap) Discard(files []protocol.FileInfo) error {
 // Drop block map, removing all entries related to this block map from the db.
 func (m *BlockMap) Drop() error {
 	batch := new(leveldb.Batch)
-	iter := m.db.NewIterator(util.BytesPrefix(m.blockKey(nil, "")[:1+64]), nil)
+	iter := m.db.NewIterator(util.BytesPrefix(m.db.folderPrefix(KeyTypeBlock, []byte(m.folder))), nil)
 	defer iter.Release()
 	for iter.Next() {
 		batch.Delete(iter.Key())
@@ -105,14 +104,14 @@ func (m *BlockMap) Drop() error {
 }
 
 func (m *BlockMap) blockKey(hash []byte, file string) []byte {
-	return toBlockKey(hash, m.folder, file)
+	return m.db.blockKey(hash, []byte(m.folder), []byte(file))
 }
 
 type BlockFinder struct {
-	db *leveldb.DB
+	db *Instance
 }
 
-func NewBlockFinder(db *leveldb.DB) *BlockFinder {
+func NewBlockFinder(db *Instance) *BlockFinder {
 	if blockFinder != nil {
 		return blockFinder
 	}
@@ -135,12 +134,12 @@ func (f *BlockFinder) String() string {
 // satisfying block was eventually found.
 func (f *BlockFinder) Iterate(folders []string, hash []byte, iterFn func(string, string, int32) bool) bool {
 	for _, folder := range folders {
-		key := toBlockKey(hash, folder, "")
+		key := f.db.blockKey(hash, []byte(folder), nil)
 		iter := f.db.NewIterator(util.BytesPrefix(key), nil)
 		defer iter.Release()
 
 		for iter.Next() && iter.Error() == nil {
-			folder, file := fromBlockKey(iter.Key())
+			folder, file := f.db.blockKeyName(iter.Key())
 			index := int32(binary.BigEndian.Uint32(iter.Value()))
 			if iterFn(folder, osutil.NativeFilename(file), index) {
 				return true
@@ -157,39 +156,7 @@ func (f *BlockFinder) Fix(folder, file string, index int32, oldHash, newHash []b
 	binary.BigEndian.PutUint32(buf, uint32(index))
 
 	batch := new(leveldb.Batch)
-	batch.Delete(toBlockKey(oldHash, folder, file))
-	batch.Put(toBlockKey(newHash, folder, file), buf)
+	batch.Delete(f.db.blockKey(oldHash, []byte(folder), []byte(file)))
+	batch.Put(f.db.blockKey(newHash, []byte(folder), []byte(file)), buf)
 	return f.db.Write(batch, nil)
 }
-
-// m.blockKey returns a byte slice encoding the following information:
-//	   keyTypeBlock (1 byte)
-//	   folder (64 bytes)
-//	   block hash (32 bytes)
-//	   file name (variable size)
-func toBlockKey(hash []byte, folder, file string) []byte {
-	o := make([]byte, 1+64+32+len(file))
-	o[0] = KeyTypeBlock
-	copy(o[1:], []byte(folder))
-	copy(o[1+64:], []byte(hash))
-	copy(o[1+64+32:], []byte(file))
-	return o
-}
-
-func fromBlockKey(data []byte) (string, string) {
-	if len(data) < 1+64+32+1 {
-		panic("Incorrect key length")
-	}
-	if data[0] != KeyTypeBlock {
-		panic("Incorrect key type")
-	}
-
-	file := string(data[1+64+32:])
-
-	slice := data[1 : 1+64]
-	izero := bytes.IndexByte(slice, 0)
-	if izero > -1 {
-		return string(slice[:izero]), file
-	}
-	return string(slice), file
-}
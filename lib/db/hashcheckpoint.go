@@ -0,0 +1,130 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"encoding/binary"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// A HashCheckpoint records the blocks hashed so far for a large file, along
+// with what the file looked like at the time, so that hashing can continue
+// where it left off after a restart if the file is unchanged. All blocks are
+// full size, so offsets and sizes are implied by BlockSize.
+type HashCheckpoint struct {
+	Size      int64
+	Modified  int64 // nanoseconds
+	Inode     uint64
+	Algorithm protocol.HashAlgorithm
+	BlockSize int32
+	Blocks    []protocol.BlockInfo
+}
+
+const hashCheckpointHeaderLen = 8 + 8 + 8 + 4 + 4
+
+func (c HashCheckpoint) marshal() []byte {
+	l := hashCheckpointHeaderLen
+	for _, b := range c.Blocks {
+		l += 1 + len(b.Hash)
+	}
+
+	bs := make([]byte, l)
+	binary.BigEndian.PutUint64(bs[0:], uint64(c.Size))
+	binary.BigEndian.PutUint64(bs[8:], uint64(c.Modified))
+	binary.BigEndian.PutUint64(bs[16:], c.Inode)
+	binary.BigEndian.PutUint32(bs[24:], uint32(c.Algorithm))
+	binary.BigEndian.PutUint32(bs[28:], uint32(c.BlockSize))
+
+	off := hashCheckpointHeaderLen
+	for _, b := range c.Blocks {
+		bs[off] = byte(len(b.Hash))
+		copy(bs[off+1:], b.Hash)
+		off += 1 + len(b.Hash)
+	}
+
+	return bs
+}
+
+func (c *HashCheckpoint) unmarshal(bs []byte) bool {
+	if len(bs) < hashCheckpointHeaderLen {
+		return false
+	}
+
+	c.Size = int64(binary.BigEndian.Uint64(bs[0:]))
+	c.Modified = int64(binary.BigEndian.Uint64(bs[8:]))
+	c.Inode = binary.BigEndian.Uint64(bs[16:])
+	c.Algorithm = protocol.HashAlgorithm(binary.BigEndian.Uint32(bs[24:]))
+	c.BlockSize = int32(binary.BigEndian.Uint32(bs[28:]))
+	c.Blocks = nil
+
+	var offset int64
+	for bs = bs[hashCheckpointHeaderLen:]; len(bs) > 0; {
+		hl := int(bs[0])
+		if len(bs) < 1+hl {
+			return false
+		}
+		c.Blocks = append(c.Blocks, protocol.BlockInfo{
+			Offset: offset,
+			Size:   c.BlockSize,
+			Hash:   append([]byte(nil), bs[1:1+hl]...),
+		})
+		offset += int64(c.BlockSize)
+		bs = bs[1+hl:]
+	}
+
+	return true
+}
+
+// The HashCheckpointRepo stores hashing checkpoints for the files in a
+// folder.
+type HashCheckpointRepo struct {
+	db     *Instance
+	folder []byte
+}
+
+func NewHashCheckpointRepo(ldb *Instance, folder string) *HashCheckpointRepo {
+	return &HashCheckpointRepo{
+		db:     ldb,
+		folder: []byte(folder),
+	}
+}
+
+func (r *HashCheckpointRepo) key(name string) []byte {
+	return append(r.db.folderPrefix(KeyTypeHashCheckpoint, r.folder), name...)
+}
+
+// Get returns the checkpoint for the given file name, if any.
+func (r *HashCheckpointRepo) Get(name string) (HashCheckpoint, bool) {
+	var c HashCheckpoint
+	bs, err := r.db.DB.Get(r.key(name), nil)
+	if err != nil {
+		return c, false
+	}
+	if !c.unmarshal(bs) {
+		if debug {
+			l.Debugln("hash checkpoint: dropping corrupt entry for", name)
+		}
+		r.Delete(name)
+		return HashCheckpoint{}, false
+	}
+	return c, true
+}
+
+// Put stores the checkpoint for the given file name, replacing any existing
+// one.
+func (r *HashCheckpointRepo) Put(name string, c HashCheckpoint) {
+	if debug {
+		l.Debugf("hash checkpoint: storing %d blocks for %s", len(c.Blocks), name)
+	}
+	r.db.DB.Put(r.key(name), c.marshal(), nil)
+}
+
+// Delete removes the checkpoint for the given file name.
+func (r *HashCheckpointRepo) Delete(name string) {
+	r.db.DB.Delete(r.key(name), nil)
+}
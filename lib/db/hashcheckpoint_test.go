@@ -0,0 +1,65 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestHashCheckpointRepo(t *testing.T) {
+	ldb := OpenMemory()
+
+	repo1 := NewHashCheckpointRepo(ldb, "folder1")
+	repo2 := NewHashCheckpointRepo(ldb, "folder2")
+
+	if _, ok := repo1.Get("file"); ok {
+		t.Error("unexpected checkpoint in empty repo")
+	}
+
+	cp := HashCheckpoint{
+		Size:      3 << 20,
+		Modified:  1234567890123456789,
+		Inode:     42,
+		Algorithm: protocol.BLAKE2b,
+		BlockSize: 128 << 10,
+		Blocks: []protocol.BlockInfo{
+			{Offset: 0, Size: 128 << 10, Hash: bytes.Repeat([]byte{1}, 32)},
+			{Offset: 128 << 10, Size: 128 << 10, Hash: bytes.Repeat([]byte{2}, 32)},
+		},
+	}
+	repo1.Put("file", cp)
+
+	got, ok := repo1.Get("file")
+	if !ok {
+		t.Fatal("checkpoint not found")
+	}
+	if got.Size != cp.Size || got.Modified != cp.Modified || got.Inode != cp.Inode ||
+		got.Algorithm != cp.Algorithm || got.BlockSize != cp.BlockSize {
+		t.Errorf("header mismatch: %+v != %+v", got, cp)
+	}
+	if len(got.Blocks) != len(cp.Blocks) {
+		t.Fatalf("got %d blocks, expected %d", len(got.Blocks), len(cp.Blocks))
+	}
+	for i := range cp.Blocks {
+		if got.Blocks[i].Offset != cp.Blocks[i].Offset || got.Blocks[i].Size != cp.Blocks[i].Size ||
+			!bytes.Equal(got.Blocks[i].Hash, cp.Blocks[i].Hash) {
+			t.Errorf("block %d mismatch: %v != %v", i, got.Blocks[i], cp.Blocks[i])
+		}
+	}
+
+	if _, ok := repo2.Get("file"); ok {
+		t.Error("checkpoint leaked into other folder")
+	}
+
+	repo1.Delete("file")
+	if _, ok := repo1.Get("file"); ok {
+		t.Error("checkpoint not deleted")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"encoding/binary"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The InodeRepo remembers which file name each device and inode number pair
+// was last seen at, so that the scanner can recognize a file that has been
+// moved. The information is only a hint; a file system is free to reuse
+// inode numbers, so callers must verify that the old file is really gone.
+type InodeRepo struct {
+	ns   *NamespacedKV
+	seen map[string]struct{} // keys recorded since the last prune
+}
+
+func NewInodeRepo(ldb *Instance, folder string) *InodeRepo {
+	// The interned folder ID has a fixed length, so the prefix of one folder
+	// is never a prefix of another folder's keys.
+	prefix := string(ldb.folderPrefix(KeyTypeInode, []byte(folder)))
+
+	return &InodeRepo{
+		ns:   NewNamespacedKV(ldb, prefix),
+		seen: make(map[string]struct{}),
+	}
+}
+
+func inodeKey(dev, ino uint64) string {
+	var key [16]byte
+	binary.BigEndian.PutUint64(key[:], dev)
+	binary.BigEndian.PutUint64(key[8:], ino)
+	return string(key[:])
+}
+
+// Record stores the name of the file with the given device and inode
+// numbers, unless it's already known.
+func (r *InodeRepo) Record(dev, ino uint64, name string) {
+	key := inodeKey(dev, ino)
+	r.seen[key] = struct{}{}
+	if cur, ok := r.ns.String(key); ok && cur == name {
+		return
+	}
+	if debug {
+		l.Debugf("inode: storing %d:%d for path:%s", dev, ino, name)
+	}
+	r.ns.PutString(key, name)
+}
+
+// Lookup returns the file name last recorded for the given device and inode
+// numbers.
+func (r *InodeRepo) Lookup(dev, ino uint64) (string, bool) {
+	return r.ns.String(inodeKey(dev, ino))
+}
+
+// Prune removes the entries that have not been recorded since the last
+// prune, for the names that scanned returns true for. Those are the files
+// that are gone, or no longer have the same inode number, when the scan
+// covering them has recorded all files it found.
+func (r *InodeRepo) Prune(scanned func(name string) bool) {
+	it := r.ns.db.NewIterator(util.BytesPrefix(r.ns.prefix), nil)
+	batch := new(leveldb.Batch)
+	for it.Next() {
+		key := string(it.Key()[len(r.ns.prefix):])
+		if _, ok := r.seen[key]; ok || !scanned(string(it.Value())) {
+			continue
+		}
+		if debug {
+			l.Debugf("inode: dropping stale entry for path:%s", it.Value())
+		}
+		batch.Delete(it.Key())
+	}
+	it.Release()
+
+	if batch.Len() > 0 {
+		if err := r.ns.db.Write(batch, nil); err != nil {
+			panic(err)
+		}
+	}
+	r.seen = make(map[string]struct{})
+}
+
+func (r *InodeRepo) Drop() {
+	r.ns.Reset()
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package db
+
+import "testing"
+
+func TestInodeRepoFolderPrefix(t *testing.T) {
+	ldb := OpenMemory()
+
+	// The ID of the first folder is a prefix of the ID of the second.
+	repoA := NewInodeRepo(ldb, "a")
+	repoAB := NewInodeRepo(ldb, "ab")
+
+	repoA.Record(1, 1, "fileA")
+	repoAB.Record(1, 2, "fileAB")
+
+	if _, ok := repoA.Lookup(1, 2); ok {
+		t.Error("entry of folder ab visible in folder a")
+	}
+
+	// A prune of folder a where nothing was recorded since the last prune
+	// must not touch the entries of folder ab.
+	repoA.Prune(func(string) bool { return true })
+	repoA.Prune(func(string) bool { return true })
+	if _, ok := repoA.Lookup(1, 1); ok {
+		t.Error("stale entry not pruned from folder a")
+	}
+	if name, ok := repoAB.Lookup(1, 2); !ok || name != "fileAB" {
+		t.Errorf("prune of folder a removed entry of folder ab: %q, %v", name, ok)
+	}
+
+	repoA.Record(1, 1, "fileA")
+	repoA.Drop()
+	if _, ok := repoA.Lookup(1, 1); ok {
+		t.Error("entry not dropped from folder a")
+	}
+	if name, ok := repoAB.Lookup(1, 2); !ok || name != "fileAB" {
+		t.Errorf("drop of folder a removed entry of folder ab: %q, %v", name, ok)
+	}
+}
@@ -46,6 +46,11 @@ const (
 	KeyTypeDeviceStatistic
 	KeyTypeFolderStatistic
 	KeyTypeVirtualMtime
+	KeyTypeFolderIdx
+	KeyTypeDeviceIdx
+	KeyTypeMiscData
+	KeyTypeHashCheckpoint
+	KeyTypeInode
 )
 
 type fileVersion struct {
@@ -98,84 +103,15 @@ type dbWriter interface {
 // Flush batches to disk when they contain this many records.
 const batchFlushSize = 64
 
-// deviceKey returns a byte slice encoding the following information:
-//	   keyTypeDevice (1 byte)
-//	   folder (64 bytes)
-//	   device (32 bytes)
-//	   name (variable size)
-func deviceKey(folder, device, file []byte) []byte {
-	return deviceKeyInto(nil, folder, device, file)
-}
-
-func deviceKeyInto(k []byte, folder, device, file []byte) []byte {
-	reqLen := 1 + 64 + 32 + len(file)
-	if len(k) < reqLen {
-		k = make([]byte, reqLen)
-	}
-	k[0] = KeyTypeDevice
-	if len(folder) > 64 {
-		panic("folder name too long")
-	}
-	copy(k[1:], []byte(folder))
-	copy(k[1+64:], device[:])
-	copy(k[1+64+32:], []byte(file))
-	return k[:reqLen]
-}
-
-func deviceKeyName(key []byte) []byte {
-	return key[1+64+32:]
-}
-
-func deviceKeyFolder(key []byte) []byte {
-	folder := key[1 : 1+64]
-	izero := bytes.IndexByte(folder, 0)
-	if izero < 0 {
-		return folder
-	}
-	return folder[:izero]
-}
-
-func deviceKeyDevice(key []byte) []byte {
-	return key[1+64 : 1+64+32]
-}
-
-// globalKey returns a byte slice encoding the following information:
-//	   keyTypeGlobal (1 byte)
-//	   folder (64 bytes)
-//	   name (variable size)
-func globalKey(folder, file []byte) []byte {
-	k := make([]byte, 1+64+len(file))
-	k[0] = KeyTypeGlobal
-	if len(folder) > 64 {
-		panic("folder name too long")
-	}
-	copy(k[1:], []byte(folder))
-	copy(k[1+64:], []byte(file))
-	return k
-}
-
-func globalKeyName(key []byte) []byte {
-	return key[1+64:]
-}
-
-func globalKeyFolder(key []byte) []byte {
-	folder := key[1 : 1+64]
-	izero := bytes.IndexByte(folder, 0)
-	if izero < 0 {
-		return folder
-	}
-	return folder[:izero]
-}
-
-type deletionHandler func(db dbReader, batch dbWriter, folder, device, name []byte, dbi iterator.Iterator) int64
+type deletionHandler func(rd dbReader, batch dbWriter, folder, device, name []byte, dbi iterator.Iterator) int64
 
-func ldbGenericReplace(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo, deleteFn deletionHandler) int64 {
+func (db *Instance) genericReplace(folder, device []byte, fs []protocol.FileInfo, deleteFn deletionHandler) int64 {
 	runtime.GC()
 
 	sort.Sort(fileList(fs)) // sort list on name, same as in the database
 
-	start := deviceKey(folder, device, nil)                            // before all folder/device files
-	limit := deviceKey(folder, device, []byte{0xff, 0xff, 0xff, 0xff}) // after all folder/device files
+	start := db.deviceKey(folder, device, nil)                            // before all folder/device files
+	limit := db.deviceKey(folder, device, []byte{0xff, 0xff, 0xff, 0xff}) // after all folder/device files
 
 	batch := new(leveldb.Batch)
 	if debugDB {
@@ -215,7 +151,7 @@ func ldbGenericReplace(db *leveldb.DB, folder, device []byte, fs []protocol.File
 		}
 
 		if moreDb {
-			oldName = deviceKeyName(dbi.Key())
+			oldName = db.deviceKeyName(dbi.Key())
 		}
 
 		cmp := bytes.Compare(newName, oldName)
@@ -230,13 +166,13 @@ func ldbGenericReplace(db *leveldb.DB, folder, device []byte, fs []protocol.File
 				l.Debugln("generic replace; missing - insert")
 			}
 			// Database is missing this file. Insert it.
-			if lv := ldbInsert(batch, folder, device, fs[fsi]); lv > maxLocalVer {
+			if lv := db.insert(batch, folder, device, fs[fsi]); lv > maxLocalVer {
 				maxLocalVer = lv
 			}
 			if fs[fsi].IsInvalid() {
-				ldbRemoveFromGlobal(snap, batch, folder, device, newName)
+				db.removeFromGlobal(snap, batch, folder, device, newName)
 			} else {
-				ldbUpdateGlobal(snap, batch, folder, device, fs[fsi])
+				db.updateGlobal(snap, batch, folder, device, fs[fsi])
 			}
 			fsi++
 
@@ -253,13 +189,13 @@ func ldbGenericReplace(db *leveldb.DB, folder, device []byte, fs []protocol.File
 				if debugDB {
 					l.Debugln("generic replace; differs - insert")
 				}
-				if lv := ldbInsert(batch, folder, device, fs[fsi]); lv > maxLocalVer {
+				if lv := db.insert(batch, folder, device, fs[fsi]); lv > maxLocalVer {
 					maxLocalVer = lv
 				}
 				if fs[fsi].IsInvalid() {
-					ldbRemoveFromGlobal(snap, batch, folder, device, newName)
+					db.removeFromGlobal(snap, batch, folder, device, newName)
 				} else {
-					ldbUpdateGlobal(snap, batch, folder, device, fs[fsi])
+					db.updateGlobal(snap, batch, folder, device, fs[fsi])
 				}
 			} else if debugDB {
 				l.Debugln("generic replace; equal - ignore")
@@ -305,14 +241,14 @@ func ldbGenericReplace(db *leveldb.DB, folder, device []byte, fs []protocol.File
 	return maxLocalVer
 }
 
-func ldbReplace(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo) int64 {
+func (db *Instance) replace(folder, device []byte, fs []protocol.FileInfo) int64 {
 	// TODO: Return the remaining maxLocalVer?
-	return ldbGenericReplace(db, folder, device, fs, func(db dbReader, batch dbWriter, folder, device, name []byte, dbi iterator.Iterator) int64 {
+	return db.genericReplace(folder, device, fs, func(rd dbReader, batch dbWriter, folder, device, name []byte, dbi iterator.Iterator) int64 {
 		// Database has a file that we are missing. Remove it.
 		if debugDB {
 			l.Debugf("delete; folder=%q device=%v name=%q", folder, protocol.DeviceIDFromBytes(device), name)
 		}
-		ldbRemoveFromGlobal(db, batch, folder, device, name)
+		db.removeFromGlobal(rd, batch, folder, device, name)
 		if debugDB {
 			l.Debugf("batch.Delete %p %x", batch, dbi.Key())
 		}
@@ -321,7 +257,7 @@ func ldbReplace(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo) i
 	})
 }
 
-func ldbUpdate(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo) int64 {
+func (db *Instance) updateFiles(folder, device []byte, fs []protocol.FileInfo) int64 {
 	runtime.GC()
 
 	batch := new(leveldb.Batch)
@@ -346,19 +282,19 @@ func ldbUpdate(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo) in
 	var fk []byte
 	for _, f := range fs {
 		name := []byte(f.Name)
-		fk = deviceKeyInto(fk[:cap(fk)], folder, device, name)
+		fk = db.deviceKeyInto(fk[:cap(fk)], folder, device, name)
 		if debugDB {
 			l.Debugf("snap.Get %p %x", snap, fk)
 		}
 		bs, err := snap.Get(fk, nil)
 		if err == leveldb.ErrNotFound {
-			if lv := ldbInsert(batch, folder, device, f); lv > maxLocalVer {
+			if lv := db.insert(batch, folder, device, f); lv > maxLocalVer {
 				maxLocalVer = lv
 			}
 			if f.IsInvalid() {
-				ldbRemoveFromGlobal(snap, batch, folder, device, name)
+				db.removeFromGlobal(snap, batch, folder, device, name)
 			} else {
-				ldbUpdateGlobal(snap, batch, folder, device, f)
+				db.updateGlobal(snap, batch, folder, device, f)
 			}
 			continue
 		}
@@ -371,13 +307,13 @@ func ldbUpdate(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo) in
 		// Flags might change without the version being bumped when we set the
 		// invalid flag on an existing file.
 		if !ef.Version.Equal(f.Version) || ef.Flags != f.Flags {
-			if lv := ldbInsert(batch, folder, device, f); lv > maxLocalVer {
+			if lv := db.insert(batch, folder, device, f); lv > maxLocalVer {
 				maxLocalVer = lv
 			}
 			if f.IsInvalid() {
-				ldbRemoveFromGlobal(snap, batch, folder, device, name)
+				db.removeFromGlobal(snap, batch, folder, device, name)
 			} else {
-				ldbUpdateGlobal(snap, batch, folder, device, f)
+				db.updateGlobal(snap, batch, folder, device, f)
 			}
 		}
 
@@ -408,7 +344,7 @@ func ldbUpdate(db *leveldb.DB, folder, device []byte, fs []protocol.FileInfo) in
 	return maxLocalVer
 }
 
-func ldbInsert(batch dbWriter, folder, device []byte, file protocol.FileInfo) int64 {
+func (db *Instance) insert(batch dbWriter, folder, device []byte, file protocol.FileInfo) int64 {
 	if debugDB {
 		l.Debugf("insert; folder=%q device=%v %v", folder, protocol.DeviceIDFromBytes(device), file)
 	}
@@ -418,7 +354,7 @@ func ldbInsert(batch dbWriter, folder, device []byte, file protocol.FileInfo) in
 	}
 
 	name := []byte(file.Name)
-	nk := deviceKey(folder, device, name)
+	nk := db.deviceKey(folder, device, name)
 	if debugDB {
 		l.Debugf("batch.Put %p %x", batch, nk)
 	}
@@ -430,13 +366,13 @@ func ldbInsert(batch dbWriter, folder, device []byte, file protocol.FileInfo) in
 // ldbUpdateGlobal adds this device+version to the version list for the given
 // file. If the device is already present in the list, the version is updated.
 // If the file does not have an entry in the global list, it is created.
-func ldbUpdateGlobal(db dbReader, batch dbWriter, folder, device []byte, file protocol.FileInfo) bool {
+func (db *Instance) updateGlobal(rd dbReader, batch dbWriter, folder, device []byte, file protocol.FileInfo) bool {
 	if debugDB {
 		l.Debugf("update global; folder=%q device=%v file=%q version=%d", folder, protocol.DeviceIDFromBytes(device), file.Name, file.Version)
 	}
 	name := []byte(file.Name)
-	gk := globalKey(folder, name)
-	svl, err := db.Get(gk, nil)
+	gk := db.globalKey(folder, name)
+	svl, err := rd.Get(gk, nil)
 	if err != nil && err != leveldb.ErrNotFound {
 		panic(err)
 	}
@@ -484,7 +420,7 @@ func ldbUpdateGlobal(db dbReader, batch dbWriter, folder, device []byte, file pr
 			// "Greater" in the condition above is just based on the device
 			// IDs in the version vector, which is not the only thing we use
 			// to determine the winner.)
-			of, ok := ldbGet(db, folder, fl.versions[i].device, name)
+			of, ok := db.getFile(rd, folder, fl.versions[i].device, name)
 			if !ok {
 				panic("file referenced in version list does not exist")
 			}
@@ -518,13 +454,13 @@ func insertVersion(vl []fileVersion, i int, v fileVersion) []fileVersion {
 // ldbRemoveFromGlobal removes the device from the global version list for the
 // given file. If the version list is empty after this, the file entry is
 // removed entirely.
-func ldbRemoveFromGlobal(db dbReader, batch dbWriter, folder, device, file []byte) {
+func (db *Instance) removeFromGlobal(rd dbReader, batch dbWriter, folder, device, file []byte) {
 	if debugDB {
 		l.Debugf("remove from global; folder=%q device=%v file=%q", folder, protocol.DeviceIDFromBytes(device), file)
 	}
 
-	gk := globalKey(folder, file)
-	svl, err := db.Get(gk, nil)
+	gk := db.globalKey(folder, file)
+	svl, err := rd.Get(gk, nil)
 	if err != nil {
 		// We might be called to "remove" a global version that doesn't exist
 		// if the first update for the file is already marked invalid.
@@ -558,9 +494,9 @@ func ldbRemoveFromGlobal(db dbReader, batch dbWriter, folder, device, file []byt
 	}
 }
 
-func ldbWithHave(db *leveldb.DB, folder, device []byte, truncate bool, fn Iterator) {
-	start := deviceKey(folder, device, nil)                            // before all folder/device files
-	limit := deviceKey(folder, device, []byte{0xff, 0xff, 0xff, 0xff}) // after all folder/device files
+func (db *Instance) withHave(folder, device []byte, truncate bool, fn Iterator) {
+	start := db.deviceKey(folder, device, nil)                            // before all folder/device files
+	limit := db.deviceKey(folder, device, []byte{0xff, 0xff, 0xff, 0xff}) // after all folder/device files
 	snap, err := db.GetSnapshot()
 	if err != nil {
 		panic(err)
@@ -589,11 +525,9 @@ func ldbWithHave(db *leveldb.DB, folder, device []byte, truncate bool, fn Iterat
 	}
 }
 
-func ldbWithAllFolderTruncated(db *leveldb.DB, folder []byte, fn func(device []byte, f FileInfoTruncated) bool) {
+func (db *Instance) withAllFolderTruncated(folder []byte, fn func(device []byte, f FileInfoTruncated) bool) {
 	runtime.GC()
 
-	start := deviceKey(folder, nil, nil)                                                  // before all folder/device files
-	limit := deviceKey(folder, protocol.LocalDeviceID[:], []byte{0xff, 0xff, 0xff, 0xff}) // after all folder/device files
 	snap, err := db.GetSnapshot()
 	if err != nil {
 		panic(err)
@@ -608,11 +542,11 @@ func ldbWithAllFolderTruncated(db *leveldb.DB, folder []byte, fn func(device []b
 		snap.Release()
 	}()
 
-	dbi := snap.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+	dbi := snap.NewIterator(util.BytesPrefix(db.folderPrefix(KeyTypeDevice, folder)), nil)
 	defer dbi.Release()
 
 	for dbi.Next() {
-		device := deviceKeyDevice(dbi.Key())
+		device := db.deviceKeyDevice(dbi.Key())
 		var f FileInfoTruncated
 		err := f.UnmarshalXDR(dbi.Value())
 		if err != nil {
@@ -623,7 +557,7 @@ func ldbWithAllFolderTruncated(db *leveldb.DB, folder []byte, fn func(device []b
 		case "", ".", "..", "/": // A few obviously invalid filenames
 			l.Infof("Dropping invalid filename %q from database", f.Name)
 			batch := new(leveldb.Batch)
-			ldbRemoveFromGlobal(db, batch, folder, device, nil)
+			db.removeFromGlobal(db, batch, folder, device, nil)
 			batch.Delete(dbi.Key())
 			db.Write(batch, nil)
 			continue
@@ -635,9 +569,9 @@ func ldbWithAllFolderTruncated(db *leveldb.DB, folder []byte, fn func(device []b
 	}
 }
 
-func ldbGet(db dbReader, folder, device, file []byte) (protocol.FileInfo, bool) {
-	nk := deviceKey(folder, device, file)
-	bs, err := db.Get(nk, nil)
+func (db *Instance) getFile(rd dbReader, folder, device, file []byte) (protocol.FileInfo, bool) {
+	nk := db.deviceKey(folder, device, file)
+	bs, err := rd.Get(nk, nil)
 	if err == leveldb.ErrNotFound {
 		return protocol.FileInfo{}, false
 	}
@@ -653,8 +587,8 @@ func ldbGet(db dbReader, folder, device, file []byte) (protocol.FileInfo, bool)
 	return f, true
 }
 
-func ldbGetGlobal(db *leveldb.DB, folder, file []byte, truncate bool) (FileIntf, bool) {
-	k := globalKey(folder, file)
+func (db *Instance) getGlobal(folder, file []byte, truncate bool) (FileIntf, bool) {
+	k := db.globalKey(folder, file)
 	snap, err := db.GetSnapshot()
 	if err != nil {
 		panic(err)
@@ -690,7 +624,7 @@ func ldbGetGlobal(db *leveldb.DB, folder, file []byte, truncate bool) (FileIntf,
 		panic("no versions?")
 	}
 
-	k = deviceKey(folder, vl.versions[0].device, file)
+	k = db.deviceKey(folder, vl.versions[0].device, file)
 	if debugDB {
 		l.Debugf("snap.Get %p %x", snap, k)
 	}
@@ -706,7 +640,7 @@ func ldbGetGlobal(db *leveldb.DB, folder, file []byte, truncate bool) (FileIntf,
 	return fi, true
 }
 
-func ldbWithGlobal(db *leveldb.DB, folder, prefix []byte, truncate bool, fn Iterator) {
+func (db *Instance) withGlobal(folder, prefix []byte, truncate bool, fn Iterator) {
 	runtime.GC()
 
 	snap, err := db.GetSnapshot()
@@ -723,7 +657,7 @@ func ldbWithGlobal(db *leveldb.DB, folder, prefix []byte, truncate bool, fn Iter
 		snap.Release()
 	}()
 
-	dbi := snap.NewIterator(util.BytesPrefix(globalKey(folder, prefix)), nil)
+	dbi := snap.NewIterator(util.BytesPrefix(db.globalKey(folder, prefix)), nil)
 	defer dbi.Release()
 
 	var fk []byte
@@ -737,8 +671,8 @@ func ldbWithGlobal(db *leveldb.DB, folder, prefix []byte, truncate bool, fn Iter
 			l.Debugln(dbi.Key())
 			panic("no versions?")
 		}
-		name := globalKeyName(dbi.Key())
-		fk = deviceKeyInto(fk[:cap(fk)], folder, vl.versions[0].device, name)
+		name := db.globalKeyName(dbi.Key())
+		fk = db.deviceKeyInto(fk[:cap(fk)], folder, vl.versions[0].device, name)
 		if debugDB {
 			l.Debugf("snap.Get %p %x", snap, fk)
 		}
@@ -750,7 +684,7 @@ func ldbWithGlobal(db *leveldb.DB, folder, prefix []byte, truncate bool, fn Iter
 			l.Debugf("vl.versions[0].device: %x", vl.versions[0].device)
 			l.Debugf("name: %q (%x)", name, name)
 			l.Debugf("fk: %q", fk)
-			l.Debugf("fk: %x %x %x", fk[1:1+64], fk[1+64:1+64+32], fk[1+64+32:])
+			l.Debugf("fk: %x %x %x", fk[:keyPrefixLen+keyFolderLen], fk[keyPrefixLen+keyFolderLen:keyPrefixLen+keyFolderLen+keyDeviceLen], fk[keyPrefixLen+keyFolderLen+keyDeviceLen:])
 			panic(err)
 		}
 
@@ -765,8 +699,8 @@ func ldbWithGlobal(db *leveldb.DB, folder, prefix []byte, truncate bool, fn Iter
 	}
 }
 
-func ldbAvailability(db *leveldb.DB, folder, file []byte) []protocol.DeviceID {
-	k := globalKey(folder, file)
+func (db *Instance) availability(folder, file []byte) []protocol.DeviceID {
+	k := db.globalKey(folder, file)
 	bs, err := db.Get(k, nil)
 	if err == leveldb.ErrNotFound {
 		return nil
@@ -793,11 +727,11 @@ func ldbAvailability(db *leveldb.DB, folder, file []byte) []protocol.DeviceID {
 	return devices
 }
 
-func ldbWithNeed(db *leveldb.DB, folder, device []byte, truncate bool, fn Iterator) {
+func (db *Instance) withNeed(folder, device []byte, truncate bool, fn Iterator) {
 	runtime.GC()
 
-	start := globalKey(folder, nil)
-	limit := globalKey(folder, []byte{0xff, 0xff, 0xff, 0xff})
+	start := db.globalKey(folder, nil)
+	limit := db.globalKey(folder, []byte{0xff, 0xff, 0xff, 0xff})
 	snap, err := db.GetSnapshot()
 	if err != nil {
 		panic(err)
@@ -844,7 +778,7 @@ nextFile:
 		}
 
 		if need || !have {
-			name := globalKeyName(dbi.Key())
+			name := db.globalKeyName(dbi.Key())
 			needVersion := vl.versions[0].version
 
 		nextVersion:
@@ -853,7 +787,7 @@ nextFile:
 					// We haven't found a valid copy of the file with the needed version.
 					continue nextFile
 				}
-				fk = deviceKeyInto(fk[:cap(fk)], folder, vl.versions[i].device, name)
+				fk = db.deviceKeyInto(fk[:cap(fk)], folder, vl.versions[i].device, name)
 				if debugDB {
 					l.Debugf("snap.Get %p %x", snap, fk)
 				}
@@ -901,9 +835,7 @@ nextFile:
 	}
 }
 
-func ldbListFolders(db *leveldb.DB) []string {
-	runtime.GC()
-
+func (db *Instance) listFolders() []string {
 	snap, err := db.GetSnapshot()
 	if err != nil {
 		panic(err)
@@ -918,62 +850,42 @@ func ldbListFolders(db *leveldb.DB) []string {
 		snap.Release()
 	}()
 
-	dbi := snap.NewIterator(util.BytesPrefix([]byte{KeyTypeGlobal}), nil)
-	defer dbi.Release()
-
-	folderExists := make(map[string]bool)
-	for dbi.Next() {
-		folder := string(globalKeyFolder(dbi.Key()))
-		if !folderExists[folder] {
-			folderExists[folder] = true
+	// The folder index may contain folders that have since been dropped, so
+	// only return those that still have global entries.
+	var folders []string
+	for _, folder := range db.folderIdx.Values() {
+		dbi := snap.NewIterator(util.BytesPrefix(db.folderPrefix(KeyTypeGlobal, []byte(folder))), nil)
+		if dbi.Next() {
+			folders = append(folders, folder)
 		}
+		dbi.Release()
 	}
 
-	folders := make([]string, 0, len(folderExists))
-	for k := range folderExists {
-		folders = append(folders, k)
-	}
-
-	sort.Strings(folders)
 	return folders
 }
 
-func ldbDropFolder(db *leveldb.DB, folder []byte) {
+func (db *Instance) dropFolder(folder []byte) {
 	runtime.GC()
 
-	snap, err := db.GetSnapshot()
-	if err != nil {
-		panic(err)
-	}
-	if debugDB {
-		l.Debugf("created snapshot %p", snap)
-	}
-	defer func() {
-		if debugDB {
-			l.Debugf("close snapshot %p", snap)
-		}
-		snap.Release()
-	}()
-
-	// Remove all items related to the given folder from the device->file bucket
-	dbi := snap.NewIterator(util.BytesPrefix([]byte{KeyTypeDevice}), nil)
-	for dbi.Next() {
-		itemFolder := deviceKeyFolder(dbi.Key())
-		if bytes.Compare(folder, itemFolder) == 0 {
-			db.Delete(dbi.Key(), nil)
+	// Remove all items related to the given folder from the device->file
+	// bucket, the global bucket and any hashing checkpoints
+	batch := new(leveldb.Batch)
+	for _, keyType := range []byte{KeyTypeDevice, KeyTypeGlobal, KeyTypeHashCheckpoint} {
+		dbi := db.NewIterator(util.BytesPrefix(db.folderPrefix(keyType, folder)), nil)
+		for dbi.Next() {
+			batch.Delete(dbi.Key())
+			if batch.Len() > batchFlushSize {
+				if err := db.Write(batch, nil); err != nil {
+					panic(err)
+				}
+				batch.Reset()
+			}
 		}
+		dbi.Release()
 	}
-	dbi.Release()
-
-	// Remove all items related to the given folder from the global bucket
-	dbi = snap.NewIterator(util.BytesPrefix([]byte{KeyTypeGlobal}), nil)
-	for dbi.Next() {
-		itemFolder := globalKeyFolder(dbi.Key())
-		if bytes.Compare(folder, itemFolder) == 0 {
-			db.Delete(dbi.Key(), nil)
-		}
+	if err := db.Write(batch, nil); err != nil {
+		panic(err)
 	}
-	dbi.Release()
 }
 
 func unmarshalTrunc(bs []byte, truncate bool) (FileIntf, error) {
@@ -988,7 +900,7 @@ func unmarshalTrunc(bs []byte, truncate bool) (FileIntf, error) {
 	return tf, err
 }
 
-func ldbCheckGlobals(db *leveldb.DB, folder []byte) {
+func (db *Instance) checkGlobals(folder []byte) {
 	defer runtime.GC()
 
 	snap, err := db.GetSnapshot()
@@ -1005,8 +917,8 @@ func ldbCheckGlobals(db *leveldb.DB, folder []byte) {
 		snap.Release()
 	}()
 
-	start := globalKey(folder, nil)
-	limit := globalKey(folder, []byte{0xff, 0xff, 0xff, 0xff})
+	start := db.globalKey(folder, nil)
+	limit := db.globalKey(folder, []byte{0xff, 0xff, 0xff, 0xff})
 	dbi := snap.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
 	defer dbi.Release()
 
@@ -1029,10 +941,10 @@ func ldbCheckGlobals(db *leveldb.DB, folder []byte) {
 		// there are global entries pointing to no longer existing files. Here
 		// we find those and clear them out.
 
-		name := globalKeyName(gk)
+		name := db.globalKeyName(gk)
 		var newVL versionList
 		for _, version := range vl.versions {
-			fk = deviceKeyInto(fk[:cap(fk)], folder, version.device, name)
+			fk = db.deviceKeyInto(fk[:cap(fk)], folder, version.device, name)
 			if debugDB {
 				l.Debugf("snap.Get %p %x", snap, fk)
 			}
@@ -1047,7 +959,7 @@ func ldbCheckGlobals(db *leveldb.DB, folder []byte) {
 		}
 
 		if len(newVL.versions) != len(vl.versions) {
-			l.Infof("db repair: rewriting global version list for %x %x", gk[1:1+64], gk[1+64:])
+			l.Infof("db repair: rewriting global version list for %q %q", folder, name)
 			batch.Put(dbi.Key(), newVL.MustMarshalXDR())
 		}
 	}
@@ -0,0 +1,109 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"bytes"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The old key format stored the folder ID padded to 64 bytes and the full 32
+// byte device ID in every key. Folder IDs are never empty, so the byte
+// following the key type is always nonzero in an old style key. New style
+// keys start with a big endian folder index, which has a zero high byte for
+// all practical numbers of folders. This lets us tell the formats apart
+// without any further bookkeeping, and makes the conversion safe to resume
+// if it gets interrupted halfway.
+const (
+	oldKeyFolderLen = 64
+	oldKeyDeviceLen = 32
+)
+
+func isOldKey(key []byte) bool {
+	return len(key) > keyPrefixLen && key[keyPrefixLen] != 0
+}
+
+// convertKeyFormat rewrites all device, global and block keys in the old,
+// non indexed format to the current format. It returns the number of keys
+// converted.
+func (db *Instance) convertKeyFormat() int {
+	var converted int
+	for _, keyType := range []byte{KeyTypeDevice, KeyTypeGlobal, KeyTypeBlock} {
+		converted += db.convertKeyType(keyType)
+	}
+	return converted
+}
+
+func (db *Instance) convertKeyType(keyType byte) int {
+	dbi := db.NewIterator(util.BytesPrefix([]byte{keyType}), nil)
+	defer dbi.Release()
+
+	batch := new(leveldb.Batch)
+	var converted int
+	for dbi.Next() {
+		oldKey := dbi.Key()
+		if !isOldKey(oldKey) {
+			continue
+		}
+
+		var newKey []byte
+		switch keyType {
+		case KeyTypeDevice:
+			if len(oldKey) < keyPrefixLen+oldKeyFolderLen+oldKeyDeviceLen {
+				continue
+			}
+			folder := oldKeyFolder(oldKey)
+			device := oldKey[keyPrefixLen+oldKeyFolderLen : keyPrefixLen+oldKeyFolderLen+oldKeyDeviceLen]
+			name := oldKey[keyPrefixLen+oldKeyFolderLen+oldKeyDeviceLen:]
+			newKey = db.deviceKey(folder, device, name)
+
+		case KeyTypeGlobal:
+			if len(oldKey) < keyPrefixLen+oldKeyFolderLen {
+				continue
+			}
+			folder := oldKeyFolder(oldKey)
+			name := oldKey[keyPrefixLen+oldKeyFolderLen:]
+			newKey = db.globalKey(folder, name)
+
+		case KeyTypeBlock:
+			if len(oldKey) < keyPrefixLen+oldKeyFolderLen+keyHashLen {
+				continue
+			}
+			folder := oldKeyFolder(oldKey)
+			hash := oldKey[keyPrefixLen+oldKeyFolderLen : keyPrefixLen+oldKeyFolderLen+keyHashLen]
+			name := oldKey[keyPrefixLen+oldKeyFolderLen+keyHashLen:]
+			newKey = db.blockKey(hash, folder, name)
+		}
+
+		batch.Put(newKey, dbi.Value())
+		batch.Delete(oldKey)
+		converted++
+
+		if batch.Len() > batchFlushSize {
+			if err := db.Write(batch, nil); err != nil {
+				panic(err)
+			}
+			batch.Reset()
+		}
+	}
+
+	if err := db.Write(batch, nil); err != nil {
+		panic(err)
+	}
+	return converted
+}
+
+func oldKeyFolder(key []byte) []byte {
+	folder := key[keyPrefixLen : keyPrefixLen+oldKeyFolderLen]
+	if izero := bytes.IndexByte(folder, 0); izero >= 0 {
+		folder = folder[:izero]
+	}
+	// The key slice is only valid until the next iteration.
+	return append([]byte(nil), folder...)
+}
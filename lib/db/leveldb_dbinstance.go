@@ -0,0 +1,272 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/syncthing/syncthing/lib/sync"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	keyPrefixLen = 1
+	keyFolderLen = 4 // indexed
+	keyDeviceLen = 4 // indexed
+	keyHashLen   = 32
+)
+
+// The current database schema version. Databases without a recorded version
+// use the old, non indexed key format and are converted on open.
+const dbVersion = 1
+
+// Instance is a leveldb database along with the indexes mapping folder and
+// device IDs to the small integers used in keys.
+type Instance struct {
+	*leveldb.DB
+	folderIdx *smallIndex
+	deviceIdx *smallIndex
+}
+
+// NewInstance wraps the given leveldb database, loading the folder and
+// device indexes and converting any existing data from older key formats.
+func NewInstance(ldb *leveldb.DB) *Instance {
+	db := &Instance{
+		DB:        ldb,
+		folderIdx: newSmallIndex(ldb, []byte{KeyTypeFolderIdx}),
+		deviceIdx: newSmallIndex(ldb, []byte{KeyTypeDeviceIdx}),
+	}
+	db.updateSchema()
+	return db
+}
+
+// OpenMemory returns a new Instance backed by memory only storage, useful
+// for testing.
+func OpenMemory() *Instance {
+	ldb, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	return NewInstance(ldb)
+}
+
+func (db *Instance) updateSchema() {
+	misc := NewNamespacedKV(db, string([]byte{KeyTypeMiscData}))
+	if v, ok := misc.Int64("dbVersion"); ok && v >= dbVersion {
+		return
+	}
+	if n := db.convertKeyFormat(); n > 0 {
+		l.Infof("Converted %d database entries to the indexed key format", n)
+	}
+	misc.PutInt64("dbVersion", dbVersion)
+}
+
+// deviceKey returns a byte slice encoding the following information:
+//	   keyTypeDevice (1 byte)
+//	   folder (4 bytes)
+//	   device (4 bytes)
+//	   name (variable size)
+func (db *Instance) deviceKey(folder, device, file []byte) []byte {
+	return db.deviceKeyInto(nil, folder, device, file)
+}
+
+func (db *Instance) deviceKeyInto(k []byte, folder, device, file []byte) []byte {
+	reqLen := keyPrefixLen + keyFolderLen + keyDeviceLen + len(file)
+	if len(k) < reqLen {
+		k = make([]byte, reqLen)
+	}
+	k[0] = KeyTypeDevice
+	binary.BigEndian.PutUint32(k[keyPrefixLen:], db.folderIdx.ID(folder))
+	binary.BigEndian.PutUint32(k[keyPrefixLen+keyFolderLen:], db.deviceIdx.ID(device))
+	copy(k[keyPrefixLen+keyFolderLen+keyDeviceLen:], file)
+	return k[:reqLen]
+}
+
+// deviceKeyName returns the file name from the key
+func (db *Instance) deviceKeyName(key []byte) []byte {
+	return key[keyPrefixLen+keyFolderLen+keyDeviceLen:]
+}
+
+// deviceKeyFolder returns the folder name from the key
+func (db *Instance) deviceKeyFolder(key []byte) []byte {
+	folder, ok := db.folderIdx.Val(binary.BigEndian.Uint32(key[keyPrefixLen:]))
+	if !ok {
+		panic("bug: lookup of nonexistent folder ID")
+	}
+	return folder
+}
+
+// deviceKeyDevice returns the device ID from the key
+func (db *Instance) deviceKeyDevice(key []byte) []byte {
+	device, ok := db.deviceIdx.Val(binary.BigEndian.Uint32(key[keyPrefixLen+keyFolderLen:]))
+	if !ok {
+		panic("bug: lookup of nonexistent device ID")
+	}
+	return device
+}
+
+// globalKey returns a byte slice encoding the following information:
+//	   keyTypeGlobal (1 byte)
+//	   folder (4 bytes)
+//	   name (variable size)
+func (db *Instance) globalKey(folder, file []byte) []byte {
+	k := make([]byte, keyPrefixLen+keyFolderLen+len(file))
+	k[0] = KeyTypeGlobal
+	binary.BigEndian.PutUint32(k[keyPrefixLen:], db.folderIdx.ID(folder))
+	copy(k[keyPrefixLen+keyFolderLen:], file)
+	return k
+}
+
+// globalKeyName returns the filename from the key
+func (db *Instance) globalKeyName(key []byte) []byte {
+	return key[keyPrefixLen+keyFolderLen:]
+}
+
+// globalKeyFolder returns the folder name from the key
+func (db *Instance) globalKeyFolder(key []byte) []byte {
+	folder, ok := db.folderIdx.Val(binary.BigEndian.Uint32(key[keyPrefixLen:]))
+	if !ok {
+		panic("bug: lookup of nonexistent folder ID")
+	}
+	return folder
+}
+
+// blockKey returns a byte slice encoding the following information:
+//	   keyTypeBlock (1 byte)
+//	   folder (4 bytes)
+//	   block hash (32 bytes)
+//	   file name (variable size)
+func (db *Instance) blockKey(hash, folder, file []byte) []byte {
+	k := make([]byte, keyPrefixLen+keyFolderLen+keyHashLen+len(file))
+	k[0] = KeyTypeBlock
+	binary.BigEndian.PutUint32(k[keyPrefixLen:], db.folderIdx.ID(folder))
+	copy(k[keyPrefixLen+keyFolderLen:], hash)
+	copy(k[keyPrefixLen+keyFolderLen+keyHashLen:], file)
+	return k
+}
+
+// blockKeyName returns the folder name and file name from the key
+func (db *Instance) blockKeyName(key []byte) (string, string) {
+	if len(key) < keyPrefixLen+keyFolderLen+keyHashLen+1 {
+		panic("Incorrect key length")
+	}
+	if key[0] != KeyTypeBlock {
+		panic("Incorrect key type")
+	}
+	folder, ok := db.folderIdx.Val(binary.BigEndian.Uint32(key[keyPrefixLen:]))
+	if !ok {
+		panic("bug: lookup of nonexistent folder ID")
+	}
+	return string(folder), string(key[keyPrefixLen+keyFolderLen+keyHashLen:])
+}
+
+// folderPrefix returns the key prefix shared by all keys of the given type
+// (device, global or block) for the given folder.
+func (db *Instance) folderPrefix(keyType byte, folder []byte) []byte {
+	k := make([]byte, keyPrefixLen+keyFolderLen)
+	k[0] = keyType
+	binary.BigEndian.PutUint32(k[keyPrefixLen:], db.folderIdx.ID(folder))
+	return k
+}
+
+// A smallIndex is an in memory bidirectional []byte to uint32 map. It gives
+// fast lookups in both directions and persists to the database. Don't use for
+// storing more items than fit comfortably in RAM.
+type smallIndex struct {
+	db     *leveldb.DB
+	prefix []byte
+	id2val map[uint32]string
+	val2id map[string]uint32
+	nextID uint32
+	mut    sync.Mutex
+}
+
+func newSmallIndex(db *leveldb.DB, prefix []byte) *smallIndex {
+	idx := &smallIndex{
+		db:     db,
+		prefix: prefix,
+		id2val: make(map[uint32]string),
+		val2id: make(map[string]uint32),
+		mut:    sync.NewMutex(),
+	}
+	idx.load()
+	return idx
+}
+
+// load iterates over the prefix space in the database and populates the in
+// memory maps.
+func (i *smallIndex) load() {
+	it := i.db.NewIterator(util.BytesPrefix(i.prefix), nil)
+	defer it.Release()
+	for it.Next() {
+		val := string(it.Value())
+		id := binary.BigEndian.Uint32(it.Key()[len(i.prefix):])
+		i.id2val[id] = val
+		i.val2id[val] = id
+		if id >= i.nextID {
+			i.nextID = id + 1
+		}
+	}
+}
+
+// ID returns the index number for the given byte slice, allocating a new one
+// and persisting this to the database if necessary.
+func (i *smallIndex) ID(val []byte) uint32 {
+	i.mut.Lock()
+	// intentionally avoiding defer here as we want this call to be as fast as
+	// possible in the general case (folder ID already exists). The map lookup
+	// with the conversion of []byte to string is compiler optimized to not
+	// copy the []byte, which is why we don't assign it to a temp variable
+	// here.
+	if id, ok := i.val2id[string(val)]; ok {
+		i.mut.Unlock()
+		return id
+	}
+
+	id := i.nextID
+	i.nextID++
+
+	valStr := string(val)
+	i.val2id[valStr] = id
+	i.id2val[id] = valStr
+
+	key := make([]byte, len(i.prefix)+4) // prefix plus uint32 id
+	copy(key, i.prefix)
+	binary.BigEndian.PutUint32(key[len(i.prefix):], id)
+	if err := i.db.Put(key, val, nil); err != nil {
+		panic(err)
+	}
+
+	i.mut.Unlock()
+	return id
+}
+
+// Val returns the value for the given index number, or (nil, false) if there
+// is no such index number.
+func (i *smallIndex) Val(id uint32) ([]byte, bool) {
+	i.mut.Lock()
+	val, ok := i.id2val[id]
+	i.mut.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	return []byte(val), true
+}
+
+// Values returns all values currently in the index, sorted.
+func (i *smallIndex) Values() []string {
+	i.mut.Lock()
+	vals := make([]string, 0, len(i.val2id))
+	for val := range i.val2id {
+		vals = append(vals, val)
+	}
+	i.mut.Unlock()
+
+	sort.Strings(vals)
+	return vals
+}
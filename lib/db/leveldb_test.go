@@ -8,7 +8,13 @@ package db
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 func TestDeviceKey(t *testing.T) {
@@ -16,34 +22,231 @@ func TestDeviceKey(t *testing.T) {
 	dev := []byte("device67890123456789012345678901")
 	name := []byte("name")
 
-	key := deviceKey(fld, dev, name)
+	db := OpenMemory()
+	db.folderIdx.ID([]byte("first"))
+
+	key := db.deviceKey(fld, dev, name)
 
-	fld2 := deviceKeyFolder(key)
+	fld2 := db.deviceKeyFolder(key)
 	if bytes.Compare(fld2, fld) != 0 {
 		t.Errorf("wrong folder %q != %q", fld2, fld)
 	}
-	dev2 := deviceKeyDevice(key)
+	dev2 := db.deviceKeyDevice(key)
 	if bytes.Compare(dev2, dev) != 0 {
 		t.Errorf("wrong device %q != %q", dev2, dev)
 	}
-	name2 := deviceKeyName(key)
+	name2 := db.deviceKeyName(key)
 	if bytes.Compare(name2, name) != 0 {
 		t.Errorf("wrong name %q != %q", name2, name)
 	}
+	if l := len(key); l != keyPrefixLen+keyFolderLen+keyDeviceLen+len(name) {
+		t.Errorf("unexpected key length %d", l)
+	}
 }
 
 func TestGlobalKey(t *testing.T) {
 	fld := []byte("folder6789012345678901234567890123456789012345678901234567890123")
 	name := []byte("name")
 
-	key := globalKey(fld, name)
+	db := OpenMemory()
+	db.folderIdx.ID([]byte("first"))
+
+	key := db.globalKey(fld, name)
 
-	fld2 := globalKeyFolder(key)
+	fld2 := db.globalKeyFolder(key)
 	if bytes.Compare(fld2, fld) != 0 {
 		t.Errorf("wrong folder %q != %q", fld2, fld)
 	}
-	name2 := globalKeyName(key)
+	name2 := db.globalKeyName(key)
 	if bytes.Compare(name2, name) != 0 {
 		t.Errorf("wrong name %q != %q", name2, name)
 	}
 }
+
+func TestSmallIndexPersists(t *testing.T) {
+	ldb, _ := leveldb.Open(storage.NewMemStorage(), nil)
+
+	db := NewInstance(ldb)
+	a := db.folderIdx.ID([]byte("a"))
+	b := db.folderIdx.ID([]byte("b"))
+	if a == b {
+		t.Fatal("distinct values should get distinct IDs")
+	}
+	if id := db.folderIdx.ID([]byte("a")); id != a {
+		t.Errorf("repeated lookup gave ID %d, expected %d", id, a)
+	}
+
+	// A new instance on the same database should see the same index
+	db = NewInstance(ldb)
+	if id := db.folderIdx.ID([]byte("b")); id != b {
+		t.Errorf("reloaded index gave ID %d, expected %d", id, b)
+	}
+	if c := db.folderIdx.ID([]byte("c")); c == a || c == b {
+		t.Errorf("new value reused existing ID %d", c)
+	}
+	if val, ok := db.folderIdx.Val(a); !ok || string(val) != "a" {
+		t.Errorf("Val(%d) = %q, %v", a, val, ok)
+	}
+}
+
+func TestConvertKeyFormat(t *testing.T) {
+	ldb, _ := leveldb.Open(storage.NewMemStorage(), nil)
+
+	files := []protocol.FileInfo{
+		{Name: "a", Version: protocol.Vector{{ID: 1, Value: 1}}, Blocks: genBlocks(2)},
+		{Name: "b/c", Version: protocol.Vector{{ID: 1, Value: 2}}, Blocks: genBlocks(1)},
+	}
+	populateOldFormat(ldb, "folder1", protocol.LocalDeviceID, files)
+	populateOldFormat(ldb, "folder2", protocol.LocalDeviceID, files[:1])
+
+	db := NewInstance(ldb)
+
+	if folders := db.listFolders(); len(folders) != 2 || folders[0] != "folder1" || folders[1] != "folder2" {
+		t.Fatalf("unexpected folder list %v", folders)
+	}
+
+	for _, f := range files {
+		fi, ok := db.getFile(db, []byte("folder1"), protocol.LocalDeviceID[:], []byte(f.Name))
+		if !ok {
+			t.Fatalf("file %q missing after conversion", f.Name)
+		}
+		if !fi.Version.Equal(f.Version) || len(fi.Blocks) != len(f.Blocks) {
+			t.Errorf("file %q changed in conversion: %v", f.Name, fi)
+		}
+
+		gf, ok := db.getGlobal([]byte("folder1"), []byte(f.Name), false)
+		if !ok || gf.(protocol.FileInfo).Name != f.Name {
+			t.Errorf("global %q missing after conversion", f.Name)
+		}
+	}
+
+	found := NewBlockFinder(db).Iterate([]string{"folder2"}, files[0].Blocks[1].Hash, func(folder, file string, index int32) bool {
+		return folder == "folder2" && file == "a" && index == 1
+	})
+	if !found {
+		t.Error("block entry missing after conversion")
+	}
+
+	// Nothing in the old format should remain
+	it := ldb.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		switch it.Key()[0] {
+		case KeyTypeDevice, KeyTypeGlobal, KeyTypeBlock:
+			if isOldKey(it.Key()) {
+				t.Errorf("unconverted key %x", it.Key())
+			}
+		}
+	}
+}
+
+// populateOldFormat writes the given files as the local index of the folder,
+// using the key format from before the introduction of folder and device
+// indexes.
+func populateOldFormat(ldb *leveldb.DB, folder string, device protocol.DeviceID, files []protocol.FileInfo) {
+	batch := new(leveldb.Batch)
+	buf := make([]byte, 4)
+	for _, f := range files {
+		batch.Put(oldDeviceKey([]byte(folder), device[:], []byte(f.Name)), f.MustMarshalXDR())
+		vl := versionList{versions: []fileVersion{{version: f.Version, device: device[:]}}}
+		batch.Put(oldGlobalKey([]byte(folder), []byte(f.Name)), vl.MustMarshalXDR())
+		for i, b := range f.Blocks {
+			buf[3] = byte(i)
+			batch.Put(oldBlockKey(b.Hash, []byte(folder), []byte(f.Name)), buf)
+		}
+	}
+	if err := ldb.Write(batch, nil); err != nil {
+		panic(err)
+	}
+}
+
+func oldDeviceKey(folder, device, file []byte) []byte {
+	k := make([]byte, 1+64+32+len(file))
+	k[0] = KeyTypeDevice
+	copy(k[1:], folder)
+	copy(k[1+64:], device)
+	copy(k[1+64+32:], file)
+	return k
+}
+
+func oldGlobalKey(folder, file []byte) []byte {
+	k := make([]byte, 1+64+len(file))
+	k[0] = KeyTypeGlobal
+	copy(k[1:], folder)
+	copy(k[1+64:], file)
+	return k
+}
+
+func oldBlockKey(hash, folder, file []byte) []byte {
+	k := make([]byte, 1+64+32+len(file))
+	k[0] = KeyTypeBlock
+	copy(k[1:], folder)
+	copy(k[1+64:], hash)
+	copy(k[1+64+32:], file)
+	return k
+}
+
+const benchmarkFiles = 10000
+
+func benchmarkFileList() []protocol.FileInfo {
+	files := make([]protocol.FileInfo, benchmarkFiles)
+	for i := range files {
+		files[i] = protocol.FileInfo{
+			Name:    fmt.Sprintf("dir%d/file%d", i%100, i),
+			Version: protocol.Vector{{ID: 1, Value: 1}},
+			Blocks:  genBlocks(4),
+		}
+	}
+	return files
+}
+
+// keySpace returns the total size of the keys and values stored in the
+// database.
+func keySpace(ldb *leveldb.DB) (keys, values int) {
+	it := ldb.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		keys += len(it.Key())
+		values += len(it.Value())
+	}
+	return
+}
+
+func BenchmarkIterateOldKeyFormat(b *testing.B) {
+	ldb, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	populateOldFormat(ldb, "default", protocol.LocalDeviceID, benchmarkFileList())
+
+	keys, values := keySpace(ldb)
+	b.Logf("old format: %d bytes of keys, %d bytes of values", keys, values)
+
+	prefix := oldDeviceKey([]byte("default"), protocol.LocalDeviceID[:], nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := ldb.NewIterator(util.BytesPrefix(prefix), nil)
+		for it.Next() {
+			var f FileInfoTruncated
+			f.UnmarshalXDR(it.Value())
+		}
+		it.Release()
+	}
+}
+
+func BenchmarkIterateNewKeyFormat(b *testing.B) {
+	db := OpenMemory()
+	fs := NewFileSet("default", db)
+	fs.Replace(protocol.LocalDeviceID, benchmarkFileList())
+
+	keys, values := keySpace(db.DB)
+	b.Logf("new format: %d bytes of keys, %d bytes of values", keys, values)
+
+	prefix := db.deviceKey([]byte("default"), protocol.LocalDeviceID[:], nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := db.NewIterator(util.BytesPrefix(prefix), nil)
+		for it.Next() {
+			var f FileInfoTruncated
+			f.UnmarshalXDR(it.Value())
+		}
+		it.Release()
+	}
+}
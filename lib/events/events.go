@@ -9,6 +9,8 @@ package events
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	stdsync "sync"
 	"time"
 
@@ -41,6 +43,8 @@ const (
 	FolderScanProgress
 	ExternalPortMappingChanged
 	RelayStateChanged
+	FileCorrupted
+	LoginLockout
 
 	AllEvents = (1 << iota) - 1
 )
@@ -93,6 +97,10 @@ func (t EventType) String() string {
 		return "ExternalPortMappingChanged"
 	case RelayStateChanged:
 		return "RelayStateChanged"
+	case FileCorrupted:
+		return "FileCorrupted"
+	case LoginLockout:
+		return "LoginLockout"
 	default:
 		return "Unknown"
 	}
@@ -102,12 +110,34 @@ func (t EventType) MarshalText() ([]byte, error) {
 	return []byte(t.String()), nil
 }
 
+// ParseEventTypes returns the mask of the event types in the comma separated
+// list of names.
+func ParseEventTypes(names string) (EventType, error) {
+	var mask EventType
+nextName:
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		for t := EventType(1); t&AllEvents != 0; t <<= 1 {
+			if t.String() == name {
+				mask |= t
+				continue nextName
+			}
+		}
+		return 0, fmt.Errorf("unknown event type %q", name)
+	}
+	return mask, nil
+}
+
 const BufferSize = 64
 
 type Logger struct {
-	subs   map[int]*Subscription
-	nextID int
-	mutex  sync.Mutex
+	subs      map[int]*Subscription
+	lastID    int // Event IDs are consecutive, starting at one
+	nextSubID int
+	mutex     sync.Mutex
 }
 
 type Event struct {
@@ -140,16 +170,16 @@ func NewLogger() *Logger {
 
 func (l *Logger) Log(t EventType, data interface{}) {
 	l.mutex.Lock()
+	l.lastID++
 	if debug {
-		dl.Debugln("log", l.nextID, t.String(), data)
+		dl.Debugln("log", l.lastID, t.String(), data)
 	}
 	e := Event{
-		ID:   l.nextID,
+		ID:   l.lastID,
 		Time: time.Now(),
 		Type: t,
 		Data: data,
 	}
-	l.nextID++
 	for _, s := range l.subs {
 		if s.mask&t != 0 {
 			select {
@@ -169,11 +199,11 @@ func (l *Logger) Subscribe(mask EventType) *Subscription {
 	}
 	s := &Subscription{
 		mask:    mask,
-		id:      l.nextID,
+		id:      l.nextSubID,
 		events:  make(chan Event, BufferSize),
 		timeout: time.NewTimer(0),
 	}
-	l.nextID++
+	l.nextSubID++
 	l.subs[s.id] = s
 	l.mutex.Unlock()
 	return s
@@ -263,6 +293,22 @@ func (s *BufferedSubscription) pollingLoop() {
 }
 
 func (s *BufferedSubscription) Since(id int, into []Event) []Event {
+	into, _ = s.SinceMissed(id, into)
+	return into
+}
+
+// LastID returns the ID of the latest event seen by the subscription.
+func (s *BufferedSubscription) LastID() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.cur
+}
+
+// SinceMissed is like Since, but also returns the number of events after the
+// given ID that are no longer in the buffer, or that the subscription
+// dropped because it wasn't keeping up. This only makes sense for a
+// subscription to all events.
+func (s *BufferedSubscription) SinceMissed(id int, into []Event) ([]Event, int) {
 	s.mut.Lock()
 	defer s.mut.Unlock()
 
@@ -270,6 +316,7 @@ func (s *BufferedSubscription) Since(id int, into []Event) []Event {
 		s.cond.Wait()
 	}
 
+	first := len(into)
 	for i := s.next; i < len(s.buf); i++ {
 		if s.buf[i].ID > id {
 			into = append(into, s.buf[i])
@@ -281,7 +328,15 @@ func (s *BufferedSubscription) Since(id int, into []Event) []Event {
 		}
 	}
 
-	return into
+	// Event IDs are consecutive, so any gap is a missed event.
+	missed := 0
+	prev := id
+	for _, ev := range into[first:] {
+		missed += ev.ID - prev - 1
+		prev = ev.ID
+	}
+
+	return into, missed
 }
 
 // Error returns a string pointer suitable for JSON marshalling errors. It
@@ -186,3 +186,45 @@ func TestBufferedSub(t *testing.T) {
 	}
 
 }
+
+func TestBufferedSubMissed(t *testing.T) {
+	l := events.NewLogger()
+
+	s := l.Subscribe(events.AllEvents)
+	defer l.Unsubscribe(s)
+	bs := events.NewBufferedSubscription(s, 10)
+
+	for i := 0; i < 25; i++ {
+		l.Log(events.DeviceConnected, i)
+	}
+	for bs.LastID() != 25 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Events 1 through 15 have been pushed out of the buffer.
+	evs, missed := bs.SinceMissed(3, nil)
+	if missed != 12 {
+		t.Errorf("Incorrect number of missed events %d != 12", missed)
+	}
+	if len(evs) != 10 || evs[0].ID != 16 {
+		t.Errorf("Unexpected events %v", evs)
+	}
+
+	if _, missed := bs.SinceMissed(20, nil); missed != 0 {
+		t.Errorf("Incorrect number of missed events %d != 0", missed)
+	}
+}
+
+func TestParseEventTypes(t *testing.T) {
+	mask, err := events.ParseEventTypes("DeviceConnected, FolderSummary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mask != events.DeviceConnected|events.FolderSummary {
+		t.Errorf("Incorrect mask %v", mask)
+	}
+
+	if _, err := events.ParseEventTypes("DeviceConnected,Nonexistent"); err == nil {
+		t.Error("Unexpected nil error for unknown event type")
+	}
+}
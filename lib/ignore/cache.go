@@ -50,3 +50,12 @@ func (c *cache) len() int {
 	l := len(c.entries)
 	return l
 }
+
+// cacheKey returns the key of the cached result for the file. Directories
+// get their own entries, as directory only patterns may match them.
+func cacheKey(file string, isDir bool) string {
+	if isDir {
+		return file + "/"
+	}
+	return file
+}
@@ -0,0 +1,308 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// An ignore file starting with this line (blank lines aside) is interpreted
+// with the same semantics as a .gitignore file, instead of our own dialect.
+const gitignoreSyntaxLine = "#syntax gitignore"
+
+// A gitRule is a single line of a gitignore style file.
+type gitRule struct {
+	pattern  string // As written, for display
+	match    *regexp.Regexp
+	negate   bool // The pattern started with "!"
+	dirOnly  bool // The pattern ended with "/"
+	anchored bool // Matched against the path relative to the ignore file rather than the base name
+	source   string
+	line     int
+}
+
+func (r gitRule) String() string {
+	s := r.match.String()
+	if r.dirOnly {
+		s += "(?dir)"
+	}
+	if r.negate {
+		return s
+	}
+	return "(?exclude)" + s
+}
+
+func (r gitRule) asPattern() Pattern {
+	return Pattern{
+		match:   r.match,
+		include: !r.negate,
+		text:    r.pattern,
+		source:  r.source,
+		line:    r.line,
+	}
+}
+
+// A gitIgnore matches file names according to gitignore semantics. Apart
+// from the rules in the top level ignore file, each directory may contain an
+// ignore file of the same name with rules for the files below it. These are
+// loaded when first needed.
+type gitIgnore struct {
+	root   string
+	name   string               // The ignore file name, ".stignore"
+	rules  []gitRule            // The top level rules
+	nested map[string][]gitRule // Directory -> rules, nil if there are none
+}
+
+func isGitignoreSyntax(bs []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(bs))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		return line == gitignoreSyntaxLine
+	}
+	return false
+}
+
+func newGitIgnore(file string, rules []gitRule) *gitIgnore {
+	return &gitIgnore{
+		root:   filepath.Dir(file),
+		name:   filepath.Base(file),
+		rules:  rules,
+		nested: make(map[string][]gitRule),
+	}
+}
+
+// match returns true if the file, given relative to the root, is ignored.
+func (g *gitIgnore) match(file string, isDir bool) bool {
+	rule, ok := g.explain(file, isDir)
+	return ok && !rule.negate
+}
+
+// explain returns the rule deciding whether the file is ignored, if any.
+// Whether the file is a directory is given rather than looked up, as it may
+// not exist on disk yet.
+func (g *gitIgnore) explain(file string, isDir bool) (gitRule, bool) {
+	parts := strings.Split(filepath.ToSlash(file), "/")
+
+	// A file can't be included again when a parent directory is excluded.
+	for i := 1; i < len(parts); i++ {
+		rule, ok := g.matching(parts[:i], true)
+		if ok && !rule.negate {
+			return rule, true
+		}
+	}
+
+	return g.matching(parts, isDir)
+}
+
+// matching checks the path against the rules of each directory on the way
+// to it, the deepest first, and returns the first matching rule. Within each
+// ignore file the last matching rule decides.
+func (g *gitIgnore) matching(parts []string, isDir bool) (gitRule, bool) {
+	base := parts[len(parts)-1]
+	for d := len(parts) - 1; d >= 0; d-- {
+		rules := g.rulesFor(strings.Join(parts[:d], "/"))
+		rel := strings.Join(parts[d:], "/")
+		for i := len(rules) - 1; i >= 0; i-- {
+			r := rules[i]
+			target := base
+			if r.anchored {
+				target = rel
+			}
+			if !r.match.MatchString(target) {
+				continue
+			}
+			if r.dirOnly && !isDir {
+				continue
+			}
+			return r, true
+		}
+	}
+	return gitRule{}, false
+}
+
+func (g *gitIgnore) rulesFor(dir string) []gitRule {
+	if dir == "" {
+		return g.rules
+	}
+	if rules, ok := g.nested[dir]; ok {
+		return rules
+	}
+
+	var rules []gitRule
+	file := filepath.Join(g.root, filepath.FromSlash(dir), g.name)
+	fd, err := os.Open(file)
+	if err == nil {
+		rules, err = parseGitignore(fd, file)
+		fd.Close()
+		if err != nil {
+			log.Printf("Ignoring rules in %s: %v", filepath.Join(dir, g.name), err)
+			rules = nil
+		}
+	}
+	g.nested[dir] = rules
+	return rules
+}
+
+// parseGitignore parses the lines of a gitignore style file.
+func parseGitignore(r io.Reader, file string) ([]gitRule, error) {
+	var rules []gitRule
+	var lineNo int
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Trailing spaces are ignored unless escaped.
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+			line = line[:len(line)-1]
+		}
+
+		rule := gitRule{pattern: line, source: file, line: lineNo}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+
+		exp, err := gitPatternToRegexp(line)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid pattern %q in ignore file", rule.pattern)
+		}
+		rule.match = exp
+		rules = append(rules, rule)
+	}
+
+	return rules, scanner.Err()
+}
+
+// gitPatternToRegexp converts a gitignore glob into an anchored regexp
+// matching slash separated paths.
+func gitPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	var buf bytes.Buffer
+	buf.WriteString("^")
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		// As with core.ignorecase, which git sets on these.
+		buf.WriteString("(?i)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+
+		case strings.HasPrefix(pattern[i:], "**/") && (i == 0 || pattern[i-1] == '/'):
+			// Leading "**/" or "/**/" in the middle; any number of
+			// directories, including none.
+			buf.WriteString("(?:.*/)?")
+			i += 2
+
+		case strings.HasPrefix(pattern[i:], "**") && i+2 == len(pattern) && i > 0 && pattern[i-1] == '/':
+			// Trailing "/**"; everything inside.
+			buf.WriteString(".*")
+			i++
+
+		case c == '*':
+			// Other consecutive asterisks are regular asterisks.
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+			buf.WriteString("[^/]*")
+
+		case c == '?':
+			buf.WriteString("[^/]")
+
+		case c == '[':
+			end := bracketEnd(pattern, i)
+			if end < 0 {
+				buf.WriteString(`\[`)
+				continue
+			}
+			buf.WriteString(bracketToRegexp(pattern[i+1 : end]))
+			i = end
+
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
+
+// bracketEnd returns the index of the "]" closing the bracket expression
+// starting at pattern[start], or -1 if it isn't closed.
+func bracketEnd(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		i++
+	}
+	if i < len(pattern) && pattern[i] == ']' {
+		// A leading "]" is part of the set.
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func bracketToRegexp(set string) string {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	if strings.HasPrefix(set, "!") || strings.HasPrefix(set, "^") {
+		buf.WriteString("^/")
+		set = set[1:]
+	}
+	for i := 0; i < len(set); i++ {
+		switch c := set[i]; {
+		case c == '\\' && i+1 < len(set):
+			i++
+			buf.WriteString(regexp.QuoteMeta(set[i : i+1]))
+		case c == '-' && i > 0 && i < len(set)-1:
+			buf.WriteByte('-')
+		case c == '[' || c == ']' || c == '-' || c == '^':
+			buf.WriteString(`\` + string(c))
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteString("]")
+	return buf.String()
+}
@@ -12,6 +12,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -26,6 +27,9 @@ import (
 type Pattern struct {
 	match   *regexp.Regexp
 	include bool
+	text    string // The line in the ignore file the pattern came from
+	source  string // The ignore file
+	line    int    // The line number in the ignore file, starting at one
 }
 
 func (p Pattern) String() string {
@@ -35,8 +39,33 @@ func (p Pattern) String() string {
 	return "(?exclude)" + p.match.String()
 }
 
+// Include returns true if files matching the pattern are ignored.
+func (p Pattern) Include() bool {
+	return p.include
+}
+
+// Text returns the pattern as written in the ignore file.
+func (p Pattern) Text() string {
+	return p.text
+}
+
+// Source returns the ignore file and line number the pattern came from.
+func (p Pattern) Source() (string, int) {
+	return p.source, p.line
+}
+
+// An Explanation describes why a file is or isn't ignored.
+type Explanation struct {
+	Ignored bool
+	Matched bool    // Some pattern matched the file
+	Pattern Pattern // The deciding pattern, when Matched
+	Cached  bool    // The result was already in the match cache
+}
+
 type Matcher struct {
 	patterns  []Pattern
+	index     *patternIndex
+	git       *gitIgnore // Set when the ignore file uses gitignore syntax
 	withCache bool
 	matches   *cache
 	curHash   string
@@ -74,19 +103,33 @@ func (m *Matcher) Parse(r io.Reader, file string) error {
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if isGitignoreSyntax(bs) {
+		return m.parseGitignore(bs, file)
+	}
+
 	seen := map[string]bool{file: true}
-	patterns, err := parseIgnoreFile(r, file, seen)
+	patterns, err := parseIgnoreFile(bytes.NewReader(bs), file, seen)
 	// Error is saved and returned at the end. We process the patterns
 	// (possibly blank) anyway.
 
 	newHash := hashPatterns(patterns)
 	if newHash == m.curHash {
-		// We've already loaded exactly these patterns.
+		// We've already loaded exactly these patterns and the cached
+		// results are still valid, but they may have moved around in the
+		// file.
+		m.patterns = patterns
+		m.index = newPatternIndex(patterns)
 		return err
 	}
 
 	m.curHash = newHash
 	m.patterns = patterns
+	m.index = newPatternIndex(patterns)
+	m.git = nil
 	if m.withCache {
 		m.matches = newCache(patterns)
 	}
@@ -94,7 +137,31 @@ func (m *Matcher) Parse(r io.Reader, file string) error {
 	return err
 }
 
-func (m *Matcher) Match(file string) (result bool) {
+func (m *Matcher) parseGitignore(bs []byte, file string) error {
+	rules, err := parseGitignore(bytes.NewReader(bs), file)
+
+	// The ignore files in subdirectories may have changed even when the top
+	// level one hasn't, so the loaded rules and cached results are always
+	// discarded.
+	m.patterns = nil
+	m.index = nil
+	m.git = newGitIgnore(file, rules)
+	m.curHash = hashGitRules(rules)
+	if m.withCache {
+		m.matches = newCache(nil)
+	}
+
+	return err
+}
+
+// Match returns true if the file, which is not a directory, is ignored.
+func (m *Matcher) Match(file string) bool {
+	return m.MatchFile(file, false)
+}
+
+// MatchFile returns true if the file is ignored. Patterns that only apply to
+// directories match when isDir is set.
+func (m *Matcher) MatchFile(file string, isDir bool) (result bool) {
 	if m == nil {
 		return false
 	}
@@ -102,34 +169,69 @@ func (m *Matcher) Match(file string) (result bool) {
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
-	if len(m.patterns) == 0 {
+	if len(m.patterns) == 0 && m.git == nil {
 		return false
 	}
 
 	if m.matches != nil {
 		// Check the cache for a known result.
-		res, ok := m.matches.get(file)
+		key := cacheKey(file, isDir)
+		res, ok := m.matches.get(key)
 		if ok {
 			return res
 		}
 
 		// Update the cache with the result at return time
 		defer func() {
-			m.matches.set(file, result)
+			m.matches.set(key, result)
 		}()
 	}
 
-	// Check all the patterns for a match.
-	for _, pattern := range m.patterns {
-		if pattern.match.MatchString(file) {
-			return pattern.include
-		}
+	if m.git != nil {
+		return m.git.match(file, isDir)
+	}
+
+	// Find the first matching pattern.
+	if pattern, ok := m.index.match(file); ok {
+		return pattern.include
 	}
 
 	// Default to false.
 	return false
 }
 
+// Explain returns the pattern deciding whether the file is ignored. It's
+// the same as MatchFile but slower, as the cache only holds the results and
+// the patterns are evaluated each time.
+func (m *Matcher) Explain(file string, isDir bool) Explanation {
+	var exp Explanation
+	if m == nil {
+		return exp
+	}
+
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	if m.matches != nil {
+		exp.Ignored, exp.Cached = m.matches.get(cacheKey(file, isDir))
+	}
+
+	if m.git != nil {
+		rule, ok := m.git.explain(file, isDir)
+		if ok {
+			exp.Matched = true
+			exp.Pattern = rule.asPattern()
+		}
+	} else if m.index != nil {
+		exp.Pattern, exp.Matched = m.index.match(file)
+	}
+
+	if !exp.Cached {
+		exp.Ignored = exp.Matched && exp.Pattern.include
+	}
+	return exp
+}
+
 // Patterns return a list of the loaded regexp patterns, as strings
 func (m *Matcher) Patterns() []string {
 	if m == nil {
@@ -139,6 +241,14 @@ func (m *Matcher) Patterns() []string {
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
+	if m.git != nil {
+		patterns := make([]string, len(m.git.rules))
+		for i, rule := range m.git.rules {
+			patterns[i] = rule.String()
+		}
+		return patterns
+	}
+
 	patterns := make([]string, len(m.patterns))
 	for i, pat := range m.patterns {
 		patterns[i] = pat.String()
@@ -182,6 +292,16 @@ func hashPatterns(patterns []Pattern) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
+func hashGitRules(rules []gitRule) string {
+	h := md5.New()
+	h.Write([]byte(gitignoreSyntaxLine + "\n"))
+	for _, rule := range rules {
+		h.Write([]byte(rule.String()))
+		h.Write([]byte("\n"))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
 func loadIgnoreFile(file string, seen map[string]bool) ([]Pattern, error) {
 	if seen[file] {
 		return nil, fmt.Errorf("Multiple include of ignore file %q", file)
@@ -199,6 +319,18 @@ func loadIgnoreFile(file string, seen map[string]bool) ([]Pattern, error) {
 
 func parseIgnoreFile(fd io.Reader, currentFile string, seen map[string]bool) ([]Pattern, error) {
 	var patterns []Pattern
+	var text string
+	var lineNo int
+
+	newPattern := func(exp *regexp.Regexp, include bool) Pattern {
+		return Pattern{
+			match:   exp,
+			include: include,
+			text:    text,
+			source:  currentFile,
+			line:    lineNo,
+		}
+	}
 
 	addPattern := func(line string) error {
 		include := true
@@ -219,20 +351,20 @@ func parseIgnoreFile(fd io.Reader, currentFile string, seen map[string]bool) ([]
 			if err != nil {
 				return fmt.Errorf("Invalid pattern %q in ignore file", line)
 			}
-			patterns = append(patterns, Pattern{exp, include})
+			patterns = append(patterns, newPattern(exp, include))
 		} else if strings.HasPrefix(line, "**/") {
 			// Add the pattern as is, and without **/ so it matches in current dir
 			exp, err := fnmatch.Convert(line, flags)
 			if err != nil {
 				return fmt.Errorf("Invalid pattern %q in ignore file", line)
 			}
-			patterns = append(patterns, Pattern{exp, include})
+			patterns = append(patterns, newPattern(exp, include))
 
 			exp, err = fnmatch.Convert(line[3:], flags)
 			if err != nil {
 				return fmt.Errorf("Invalid pattern %q in ignore file", line)
 			}
-			patterns = append(patterns, Pattern{exp, include})
+			patterns = append(patterns, newPattern(exp, include))
 		} else if strings.HasPrefix(line, "#include ") {
 			includeFile := filepath.Join(filepath.Dir(currentFile), line[len("#include "):])
 			includes, err := loadIgnoreFile(includeFile, seen)
@@ -248,13 +380,13 @@ func parseIgnoreFile(fd io.Reader, currentFile string, seen map[string]bool) ([]
 			if err != nil {
 				return fmt.Errorf("Invalid pattern %q in ignore file", line)
 			}
-			patterns = append(patterns, Pattern{exp, include})
+			patterns = append(patterns, newPattern(exp, include))
 
 			exp, err = fnmatch.Convert("**/"+line, flags)
 			if err != nil {
 				return fmt.Errorf("Invalid pattern %q in ignore file", line)
 			}
-			patterns = append(patterns, Pattern{exp, include})
+			patterns = append(patterns, newPattern(exp, include))
 		}
 		return nil
 	}
@@ -263,6 +395,8 @@ func parseIgnoreFile(fd io.Reader, currentFile string, seen map[string]bool) ([]
 	var err error
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
+		text = line
+		lineNo++
 		switch {
 		case line == "":
 			continue
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -509,3 +510,214 @@ func TestHashOfEmpty(t *testing.T) {
 		t.Error("there are more than zero patterns")
 	}
 }
+
+func TestGitignoreSyntax(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gitignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Only the directory holding a nested ignore file needs to exist.
+	if err := os.Mkdir(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	stignore := `
+#syntax gitignore
+# A comment, not an include
+/build
+doc/*.txt
+src/vendor/
+*.log
+!important.log
+logs/
+!logs/keep.log
+keep/*
+!keep/this
+\#hash
+\!bang
+trailing   
+escaped\ 
+`
+	err = ioutil.WriteFile(filepath.Join(dir, "src", ".stignore"), []byte("*.o\n!main.o\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pats := New(true)
+	if err := pats.Parse(bytes.NewBufferString(stignore), filepath.Join(dir, ".stignore")); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		f string
+		r bool
+	}{
+		// Anchored by the leading slash
+		{"build", true},
+		{"build/file", true},
+		{"src/build", false},
+		{"src/build/file", false},
+
+		// Anchored by the embedded slash; no implicit "**/"
+		{"doc/a.txt", true},
+		{"doc/sub/a.txt", false},
+		{"src/doc/a.txt", false},
+
+		// Directory only, given with a trailing slash when a directory
+		{"src/vendor/", true},
+		{"src/vendor", false},
+		{"src/vendor/lib.go", true},
+		{"logs/", true},
+
+		// Negation, which can't re-include files in excluded directories
+		{"debug.log", true},
+		{"src/debug.log", true},
+		{"important.log", false},
+		{"logs/keep.log", true},
+		{"keep/other", true},
+		{"keep/this", false},
+
+		// Escaping and trailing spaces
+		{"#hash", true},
+		{"!bang", true},
+		{"trailing", true},
+		{"trailing   ", false},
+		{"escaped ", true},
+		{"escaped", false},
+
+		// Nested ignore files
+		{"src/a.o", true},
+		{"src/main.o", false},
+		{"src/sub/b.o", true},
+		{"a.o", false},
+
+		{"afile", false},
+	}
+
+	for _, tc := range tests {
+		name := strings.TrimSuffix(tc.f, "/")
+		if r := pats.MatchFile(filepath.FromSlash(name), name != tc.f); r != tc.r {
+			t.Errorf("Incorrect match for %q; E: %v, A: %v", tc.f, tc.r, r)
+		}
+	}
+}
+
+func TestGitignoreSyntaxDirOnly(t *testing.T) {
+	// Nothing exists on disk; whether a name is a directory is given, as it
+	// may only exist on another device.
+	pats := New(true)
+	err := pats.Parse(bytes.NewBufferString("#syntax gitignore\nadir/\n"), filepath.Join("nonexistent", ".stignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Twice, the second time from the cache.
+	for i := 0; i < 2; i++ {
+		if !pats.MatchFile("adir", true) {
+			t.Error("directory should match a directory only pattern")
+		}
+		if pats.MatchFile("adir", false) {
+			t.Error("file should not match a directory only pattern")
+		}
+		if !pats.MatchFile(filepath.Join("adir", "file"), false) {
+			t.Error("file in a matching directory should be ignored")
+		}
+	}
+	if exp := pats.Explain("adir", true); !exp.Ignored || exp.Pattern.Text() != "adir/" {
+		t.Errorf("unexpected explanation %+v", exp)
+	}
+}
+
+func TestGitignoreSyntaxHash(t *testing.T) {
+	p1 := New(false)
+	if err := p1.Parse(bytes.NewBufferString("#syntax gitignore\nfoo\n"), ".stignore"); err != nil {
+		t.Fatal(err)
+	}
+	p2 := New(false)
+	if err := p2.Parse(bytes.NewBufferString("foo\n"), ".stignore"); err != nil {
+		t.Fatal(err)
+	}
+	if p1.Hash() == p2.Hash() {
+		t.Error("the syntax mode should be part of the hash")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	stignore := `
+	// A comment
+	!keep.txt
+	*.txt
+
+	(?i)/Build/
+	`
+
+	pats := New(true)
+	if err := pats.Parse(bytes.NewBufferString(stignore), ".stignore"); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		f       string
+		ignored bool
+		matched bool
+		text    string
+		line    int
+	}{
+		{"keep.txt", false, true, "!keep.txt", 3},
+		{"other.txt", true, true, "*.txt", 4},
+		{filepath.Join("dir", "other.txt"), true, true, "*.txt", 4},
+		{filepath.Join("build", "file"), true, true, "(?i)/Build/", 6},
+		{"other.dat", false, false, "", 0},
+	}
+
+	for _, tc := range tests {
+		exp := pats.Explain(tc.f, false)
+		if exp.Cached {
+			t.Errorf("%s: unexpected cached result", tc.f)
+		}
+		if exp.Ignored != tc.ignored || exp.Matched != tc.matched {
+			t.Errorf("%s: incorrect result; E: %v %v, A: %v %v", tc.f, tc.ignored, tc.matched, exp.Ignored, exp.Matched)
+			continue
+		}
+		if !tc.matched {
+			continue
+		}
+		if exp.Pattern.Text() != tc.text {
+			t.Errorf("%s: incorrect pattern; E: %q, A: %q", tc.f, tc.text, exp.Pattern.Text())
+		}
+		if file, line := exp.Pattern.Source(); file != ".stignore" || line != tc.line {
+			t.Errorf("%s: incorrect source; E: .stignore:%d, A: %s:%d", tc.f, tc.line, file, line)
+		}
+		if exp.Pattern.Include() != tc.ignored {
+			t.Errorf("%s: incorrect pattern type", tc.f)
+		}
+	}
+
+	pats.Match("other.txt")
+	if exp := pats.Explain("other.txt", false); !exp.Cached || !exp.Ignored {
+		t.Errorf("expected a cached ignored result, got %+v", exp)
+	}
+}
+
+func TestExplainGitignoreSyntax(t *testing.T) {
+	pats := New(false)
+	err := pats.Parse(bytes.NewBufferString("#syntax gitignore\nlogs/*\n!logs/keep.log\n"), ".stignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := pats.Explain("logs/keep.log", false)
+	if exp.Ignored || !exp.Matched || exp.Pattern.Text() != "!logs/keep.log" {
+		t.Errorf("unexpected explanation %+v", exp)
+	}
+	if _, line := exp.Pattern.Source(); line != 3 {
+		t.Errorf("incorrect line %d != 3", line)
+	}
+
+	exp = pats.Explain("logs/other.log", false)
+	if !exp.Ignored || exp.Pattern.Text() != "logs/*" {
+		t.Errorf("unexpected explanation %+v", exp)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package ignore
+
+import (
+	"os"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A patternIndex finds the first pattern matching a file name without
+// evaluating every pattern. Most patterns require the name to start or end
+// with some literal string, or to contain some literal within a path
+// component. The patterns are grouped by these literals, so that only the
+// patterns whose literal is present in the name need to be evaluated, in the
+// original order. Patterns without any such literal are always evaluated.
+type patternIndex struct {
+	patterns []Pattern
+	exact    literalIndex // Literals matched case sensitively
+	folded   literalIndex // Literals matched case insensitively
+	always   []int        // Patterns that must always be evaluated
+}
+
+type literalIndex struct {
+	fold         bool
+	prefixes     *trieNode        // Keyed on the prefix of the name
+	suffixes     *trieNode        // Keyed on the reversed suffix of the name
+	components   map[string][]int // Keyed on a whole path component
+	compPrefixes *trieNode        // Keyed on the prefix of some path component
+	compSuffixes *trieNode        // Keyed on the reversed suffix of some path component
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	patterns []int
+}
+
+type literalKind int
+
+const (
+	literalNone literalKind = iota
+	literalPrefix
+	literalSuffix
+	literalComponent
+	literalCompPrefix
+	literalCompSuffix
+)
+
+// A requiredLiteral is a literal string all names matching a pattern
+// contain, and where.
+type requiredLiteral struct {
+	kind literalKind
+	key  string
+	fold bool
+}
+
+func newPatternIndex(patterns []Pattern) *patternIndex {
+	idx := &patternIndex{
+		patterns: patterns,
+		exact:    newLiteralIndex(false),
+		folded:   newLiteralIndex(true),
+	}
+
+	for i, pat := range patterns {
+		lit := requiredLiteralOf(pat.match.String())
+		li := &idx.exact
+		if lit.fold {
+			li = &idx.folded
+		}
+
+		switch lit.kind {
+		case literalPrefix:
+			li.prefixes.insert(lit.key, i, li.fold, false)
+		case literalSuffix:
+			li.suffixes.insert(lit.key, i, li.fold, true)
+		case literalComponent:
+			key := foldString(lit.key, li.fold)
+			li.components[key] = append(li.components[key], i)
+		case literalCompPrefix:
+			li.compPrefixes.insert(lit.key, i, li.fold, false)
+		case literalCompSuffix:
+			li.compSuffixes.insert(lit.key, i, li.fold, true)
+		default:
+			idx.always = append(idx.always, i)
+		}
+	}
+
+	return idx
+}
+
+func newLiteralIndex(fold bool) literalIndex {
+	return literalIndex{
+		fold:         fold,
+		prefixes:     &trieNode{},
+		suffixes:     &trieNode{},
+		components:   make(map[string][]int),
+		compPrefixes: &trieNode{},
+		compSuffixes: &trieNode{},
+	}
+}
+
+// match returns the first pattern matching the file, if any.
+func (idx *patternIndex) match(file string) (Pattern, bool) {
+	candidates := append([]int(nil), idx.always...)
+	candidates = idx.exact.candidates(file, candidates)
+	candidates = idx.folded.candidates(file, candidates)
+	sort.Ints(candidates)
+
+	for j, i := range candidates {
+		if j > 0 && candidates[j-1] == i {
+			continue
+		}
+		if idx.patterns[i].match.MatchString(file) {
+			return idx.patterns[i], true
+		}
+	}
+	return Pattern{}, false
+}
+
+func (li *literalIndex) candidates(file string, res []int) []int {
+	res = li.prefixes.collect(file, li.fold, false, res)
+	res = li.suffixes.collect(file, li.fold, true, res)
+	if len(li.components) == 0 && li.compPrefixes.children == nil && li.compSuffixes.children == nil {
+		return res
+	}
+	for _, comp := range strings.Split(file, string(os.PathSeparator)) {
+		if len(li.components) > 0 {
+			res = append(res, li.components[foldString(comp, li.fold)]...)
+		}
+		res = li.compPrefixes.collect(comp, li.fold, false, res)
+		res = li.compSuffixes.collect(comp, li.fold, true, res)
+	}
+	return res
+}
+
+func (n *trieNode) insert(key string, pattern int, fold, reverse bool) {
+	forEachRune(key, reverse, func(r rune) bool {
+		if fold {
+			r = foldRune(r)
+		}
+		child, ok := n.children[r]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[rune]*trieNode)
+			}
+			child = &trieNode{}
+			n.children[r] = child
+		}
+		n = child
+		return true
+	})
+	n.patterns = append(n.patterns, pattern)
+}
+
+// collect appends the patterns of all nodes on the path spelled by the key.
+func (n *trieNode) collect(key string, fold, reverse bool, res []int) []int {
+	if n.children == nil {
+		return res
+	}
+	forEachRune(key, reverse, func(r rune) bool {
+		if fold {
+			r = foldRune(r)
+		}
+		n = n.children[r]
+		if n == nil {
+			return false
+		}
+		res = append(res, n.patterns...)
+		return true
+	})
+	return res
+}
+
+func forEachRune(s string, reverse bool, fn func(rune) bool) {
+	if !reverse {
+		for _, r := range s {
+			if !fn(r) {
+				return
+			}
+		}
+		return
+	}
+	for len(s) > 0 {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if !fn(r) {
+			return
+		}
+		s = s[:len(s)-size]
+	}
+}
+
+// foldRune returns the smallest rune equivalent to r under simple case
+// folding, which is what case insensitive regexps match with.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+func foldString(s string, fold bool) string {
+	if !fold {
+		return s
+	}
+	return strings.Map(foldRune, s)
+}
+
+// requiredLiteralOf returns the longest literal required by the regexp. Any
+// part it can't make sense of is simply not used; the result is then less
+// selective, but never wrong.
+func requiredLiteralOf(expr string) requiredLiteral {
+	var best requiredLiteral
+
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil || re.Op != syntax.OpConcat || len(re.Sub) < 2 {
+		return best
+	}
+	subs := re.Sub
+	if subs[0].Op != syntax.OpBeginText || subs[len(subs)-1].Op != syntax.OpEndText {
+		return best
+	}
+	subs = subs[1 : len(subs)-1]
+
+	// All literals are required to have the same case sensitivity, as they
+	// go in the same index.
+	fold := false
+	for _, sub := range subs {
+		if sub.Op == syntax.OpLiteral {
+			fold = sub.Flags&syntax.FoldCase != 0
+			break
+		}
+	}
+	best.fold = fold
+
+	literal := func(sub *syntax.Regexp) (string, bool) {
+		if sub.Op != syntax.OpLiteral || (sub.Flags&syntax.FoldCase != 0) != fold {
+			return "", false
+		}
+		return string(sub.Rune), true
+	}
+	consider := func(kind literalKind, key string) {
+		if len(key) > len(best.key) {
+			best.kind = kind
+			best.key = key
+		}
+	}
+
+	var prefix []string
+	for _, sub := range subs {
+		s, ok := literal(sub)
+		if !ok {
+			break
+		}
+		prefix = append(prefix, s)
+	}
+	consider(literalPrefix, strings.Join(prefix, ""))
+
+	var suffix []string
+	for i := len(subs) - 1; i >= len(prefix); i-- {
+		s, ok := literal(subs[i])
+		if !ok {
+			break
+		}
+		suffix = append([]string{s}, suffix...)
+	}
+	consider(literalSuffix, strings.Join(suffix, ""))
+
+	// A literal containing separators tells us about the path components
+	// on either side of them. The part before the first separator ends a
+	// component, the part after the last separator starts one, and anything
+	// in between are whole components.
+	sep := string(os.PathSeparator)
+	for _, sub := range subs {
+		s, ok := literal(sub)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(s, sep)
+		if len(parts) < 2 {
+			continue
+		}
+		for _, part := range parts[1 : len(parts)-1] {
+			consider(literalComponent, part)
+		}
+		consider(literalCompSuffix, parts[0])
+		consider(literalCompPrefix, parts[len(parts)-1])
+	}
+
+	return best
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package ignore
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+const indexTestPatterns = `
+!keep.txt
+*.txt
+(?i)*.JPG
+/build
+**/node_modules
+docs/**/draft
+src/gen/
+(?i)Thumbs.db
+a?c
+[abc]x
+*
+`
+
+func linearMatch(patterns []Pattern, file string) (Pattern, bool) {
+	for _, pat := range patterns {
+		if pat.match.MatchString(file) {
+			return pat, true
+		}
+	}
+	return Pattern{}, false
+}
+
+func TestIndexMatchesLinear(t *testing.T) {
+	patterns, err := parseIgnoreFile(bytes.NewBufferString(indexTestPatterns), ".stignore", map[string]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := newPatternIndex(patterns)
+
+	// Check each name against every prefix of the pattern list, so that
+	// the fallback pattern at the end doesn't hide mistakes.
+	names := []string{
+		"keep.txt", "other.txt", filepath.Join("dir", "keep.txt"),
+		"photo.jpg", "photo.JPG", filepath.Join("a", "b", "Photo.Jpg"),
+		"build", filepath.Join("build", "x"), filepath.Join("src", "build"),
+		"node_modules", filepath.Join("a", "node_modules", "b"),
+		filepath.Join("docs", "draft"), filepath.Join("docs", "x", "y", "draft", "z"),
+		filepath.Join("src", "gen", "file.go"), "THUMBS.DB", filepath.Join("x", "thumbs.db"),
+		"abc", "ac", "bx", "dx", "other",
+	}
+
+	for n := 1; n <= len(patterns); n++ {
+		idx := newPatternIndex(patterns[:n])
+		for _, name := range names {
+			ep, eok := linearMatch(patterns[:n], name)
+			ap, aok := idx.match(name)
+			if eok != aok || eok && (ep.String() != ap.String() || ep.line != ap.line) {
+				t.Errorf("%d patterns, %q: E: %v %v, A: %v %v", n, name, eok, ep, aok, ap)
+			}
+		}
+	}
+
+	// The final line expands into four patterns, none with a literal.
+	if len(idx.always) != 4 {
+		t.Errorf("expected only the final line to be evaluated for every name, got %d patterns", len(idx.always))
+	}
+}
+
+func TestRequiredLiteralOf(t *testing.T) {
+	if filepath.Separator != '/' {
+		t.Skip("the test expressions use forward slashes")
+	}
+
+	var tests = []struct {
+		expr string
+		lit  requiredLiteral
+	}{
+		{`^foo$`, requiredLiteral{literalPrefix, "foo", false}},
+		{`^foo/.*$`, requiredLiteral{literalPrefix, "foo/", false}},
+		{`^.*/foo$`, requiredLiteral{literalSuffix, "/foo", false}},
+		{`^.*/foo/.*$`, requiredLiteral{literalComponent, "foo", false}},
+		{`^[^/]*\.ext/.*$`, requiredLiteral{literalCompSuffix, ".ext", false}},
+		{`^.*/docs/x[^/]*$`, requiredLiteral{literalComponent, "docs", false}},
+		{`^.*/xyz[^/]*/.*$`, requiredLiteral{literalCompPrefix, "xyz", false}},
+		{`^[^/]*\.txt$`, requiredLiteral{literalSuffix, ".txt", false}},
+		{`(?i)^[^/]*\.jpg$`, requiredLiteral{literalSuffix, ".JPG", true}},
+		{`^.*$`, requiredLiteral{}},
+		{`^a|b$`, requiredLiteral{}},
+	}
+
+	for _, tc := range tests {
+		if lit := requiredLiteralOf(tc.expr); lit != tc.lit {
+			t.Errorf("%s: unexpected literal %+v != %+v", tc.expr, lit, tc.lit)
+		}
+	}
+}
+
+func manyPatterns() string {
+	var buf bytes.Buffer
+	for i := 0; i < 300; i++ {
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&buf, "*.ext%d\n", i)
+		case 1:
+			fmt.Fprintf(&buf, "dir%d\n", i)
+		case 2:
+			fmt.Fprintf(&buf, "/top%d/**\n", i)
+		}
+	}
+	return buf.String()
+}
+
+var benchNames = []string{
+	"filename",
+	filepath.Join("some", "deep", "directory", "structure", "file.txt"),
+	filepath.Join("dir299", "file"),
+	filepath.Join("a", "b", "file.ext297"),
+}
+
+func BenchmarkMatchManyPatterns(b *testing.B) {
+	pats := New(false)
+	if err := pats.Parse(bytes.NewBufferString(manyPatterns()), ".stignore"); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result = pats.Match(benchNames[i%len(benchNames)])
+	}
+}
+
+func BenchmarkMatchManyPatternsLinear(b *testing.B) {
+	patterns, err := parseIgnoreFile(bytes.NewBufferString(manyPatterns()), ".stignore", map[string]bool{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, result = linearMatch(patterns, benchNames[i%len(benchNames)])
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+)
+
+var (
+	errNoSuchFile  = errors.New("no such file")
+	errNotRegular  = errors.New("not a regular file")
+	errInvalidSeek = errors.New("invalid seek")
+)
+
+// A GlobalFile reads the contents of the global version of a file. Blocks
+// available locally, in any folder, are read from disk and the rest are
+// requested from the connected devices that have them.
+type GlobalFile struct {
+	model  *Model
+	folder string
+	file   protocol.FileInfo
+	offset int64
+
+	// The most recently read block, as reads are typically much smaller
+	// than blocks.
+	cached    int
+	cachedBuf []byte
+}
+
+// OpenGlobal returns a reader for the contents of the global version of the
+// file.
+func (m *Model) OpenGlobal(folder, file string) (*GlobalFile, error) {
+	f, ok := m.CurrentGlobalFile(folder, file)
+	if !ok || f.IsDeleted() || f.IsInvalid() {
+		return nil, errNoSuchFile
+	}
+	if f.IsDirectory() || f.IsSymlink() {
+		return nil, errNotRegular
+	}
+
+	return &GlobalFile{
+		model:  m,
+		folder: folder,
+		file:   f,
+		cached: -1,
+	}, nil
+}
+
+// Name returns the name of the file, relative to the folder root.
+func (g *GlobalFile) Name() string {
+	return g.file.Name
+}
+
+// Size returns the size of the global version of the file.
+func (g *GlobalFile) Size() int64 {
+	return g.file.Size()
+}
+
+// ModTime returns the modification time of the global version of the file.
+func (g *GlobalFile) ModTime() time.Time {
+	return time.Unix(g.file.Modified, 0)
+}
+
+func (g *GlobalFile) Read(p []byte) (int, error) {
+	if g.offset >= g.Size() {
+		return 0, io.EOF
+	}
+
+	idx := int(g.offset / protocol.BlockSize)
+	if idx != g.cached {
+		buf, err := g.readBlock(g.file.Blocks[idx])
+		if err != nil {
+			return 0, err
+		}
+		g.cached, g.cachedBuf = idx, buf
+	}
+
+	block := g.file.Blocks[idx]
+	n := copy(p, g.cachedBuf[g.offset-block.Offset:])
+	g.offset += int64(n)
+	return n, nil
+}
+
+func (g *GlobalFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case os.SEEK_SET:
+	case os.SEEK_CUR:
+		offset += g.offset
+	case os.SEEK_END:
+		offset += g.Size()
+	default:
+		return g.offset, errInvalidSeek
+	}
+	if offset < 0 {
+		return g.offset, errInvalidSeek
+	}
+	g.offset = offset
+	return offset, nil
+}
+
+// readBlock returns the verified contents of the block, from local files if
+// possible and otherwise from the connected devices.
+func (g *GlobalFile) readBlock(block protocol.BlockInfo) ([]byte, error) {
+	algo := g.file.HashAlgorithm()
+	buf := make([]byte, block.Size)
+
+	if scanner.IsZeroBlock(block, algo) {
+		return buf, nil
+	}
+
+	folderRoots := make(map[string]string)
+	var folders []string
+	g.model.fmut.RLock()
+	for folder, cfg := range g.model.folderCfgs {
+		folderRoots[folder] = cfg.Path()
+		folders = append(folders, folder)
+	}
+	g.model.fmut.RUnlock()
+
+	found := g.model.finder.Iterate(folders, block.Hash, func(folder, file string, index int32) bool {
+		fd, err := os.Open(filepath.Join(folderRoots[folder], file))
+		if err != nil {
+			return false
+		}
+		_, err = fd.ReadAt(buf, protocol.BlockSize*int64(index))
+		fd.Close()
+		if err != nil {
+			return false
+		}
+		_, err = scanner.VerifyBuffer(buf, block, algo)
+		return err == nil
+	})
+	if found {
+		return buf, nil
+	}
+
+	var lastError error
+	potentialDevices := g.model.Availability(g.folder, g.file.Name)
+	for {
+		selected := activity.leastBusy(potentialDevices)
+		if selected == (protocol.DeviceID{}) {
+			if lastError != nil {
+				return nil, lastError
+			}
+			return nil, errNoDevice
+		}
+		potentialDevices = removeDevice(potentialDevices, selected)
+
+		activity.using(selected)
+		buf, lastError = g.model.requestGlobal(selected, g.folder, g.file.Name, block.Offset, int(block.Size), block.Hash, 0, nil)
+		activity.done(selected)
+		if lastError != nil {
+			if debug {
+				l.Debugln("download request:", g.folder, g.file.Name, block.Offset, block.Size, "returned error:", lastError)
+			}
+			continue
+		}
+
+		if _, lastError = scanner.VerifyBuffer(buf, block, algo); lastError != nil {
+			lastError = fmt.Errorf("block from %s: %v", selected, lastError)
+			continue
+		}
+		return buf, nil
+	}
+}
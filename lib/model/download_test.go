@@ -0,0 +1,104 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestOpenGlobalLocal(t *testing.T) {
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(defaultFolderConfig)
+	m.StartFolderRO("default")
+	m.ServeBackground()
+	defer m.Stop()
+	m.ScanFolder("default")
+
+	expected, err := ioutil.ReadFile("testdata/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := m.OpenGlobal("default", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Size() != int64(len(expected)) {
+		t.Errorf("size %d != expected %d", g.Size(), len(expected))
+	}
+	bs, err := ioutil.ReadAll(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, expected) {
+		t.Errorf("read %q != expected %q", bs, expected)
+	}
+
+	if _, err := m.OpenGlobal("default", "nonexistent"); err == nil {
+		t.Error("opening a nonexistent file should fail")
+	}
+}
+
+func TestOpenGlobalRemote(t *testing.T) {
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(defaultFolderConfig)
+
+	data := []byte("the contents of a file only the other device has")
+	hash := sha256.Sum256(data)
+	m.AddConnection(Connection{
+		&net.TCPConn{},
+		FakeConnection{id: device1, requestData: data},
+		ConnectionTypeDirectAccept,
+	})
+	m.Index(device1, "default", []protocol.FileInfo{
+		{
+			Name:     "remote",
+			Modified: time.Now().Unix(),
+			Version:  protocol.Vector{{ID: 42, Value: 1}},
+			Blocks:   []protocol.BlockInfo{{Offset: 0, Size: int32(len(data)), Hash: hash[:]}},
+		},
+		{
+			Name:     "corrupt",
+			Modified: time.Now().Unix(),
+			Version:  protocol.Vector{{ID: 42, Value: 1}},
+			Blocks:   []protocol.BlockInfo{{Offset: 0, Size: int32(len(data)), Hash: []byte("some other hash")}},
+		},
+	}, 0, nil)
+
+	g, err := m.OpenGlobal("default", "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.Seek(4, os.SEEK_SET); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := ioutil.ReadAll(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, data[4:]) {
+		t.Errorf("read %q != expected %q", bs, data[4:])
+	}
+
+	g, err = m.OpenGlobal("default", "corrupt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(g); err == nil {
+		t.Error("reading a block with the wrong hash should fail")
+	}
+}
@@ -18,6 +18,7 @@ type folderState int
 const (
 	FolderIdle folderState = iota
 	FolderScanning
+	FolderScanWaiting
 	FolderSyncing
 	FolderError
 )
@@ -28,6 +29,8 @@ func (s folderState) String() string {
 		return "idle"
 	case FolderScanning:
 		return "scanning"
+	case FolderScanWaiting:
+		return "scan-waiting"
 	case FolderSyncing:
 		return "syncing"
 	case FolderError:
@@ -37,6 +40,13 @@ func (s folderState) String() string {
 	}
 }
 
+// A StateTotal is the number of times a folder has left a state, and the
+// total time it spent there.
+type StateTotal struct {
+	Count    int
+	Duration time.Duration
+}
+
 type stateTracker struct {
 	folder string
 
@@ -44,6 +54,7 @@ type stateTracker struct {
 	current folderState
 	err     error
 	changed time.Time
+	totals  map[folderState]StateTotal
 }
 
 // setState sets the new folder state, for states other than FolderError.
@@ -70,6 +81,7 @@ func (s *stateTracker) setState(newState folderState) {
 			eventData["duration"] = time.Since(s.changed).Seconds()
 		}
 
+		s.countTime()
 		s.current = newState
 		s.changed = time.Now()
 
@@ -87,6 +99,32 @@ func (s *stateTracker) getState() (current folderState, changed time.Time, err e
 	return
 }
 
+// getStateTotals returns the totals for the states the folder has left.
+func (s *stateTracker) getStateTotals() map[string]StateTotal {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	res := make(map[string]StateTotal, len(s.totals))
+	for state, total := range s.totals {
+		res[state.String()] = total
+	}
+	return res
+}
+
+// countTime adds the time spent in the current state, which is about to be
+// left, to the totals. Must be called with the lock held.
+func (s *stateTracker) countTime() {
+	if s.changed.IsZero() {
+		return
+	}
+	if s.totals == nil {
+		s.totals = make(map[folderState]StateTotal)
+	}
+	total := s.totals[s.current]
+	total.Count++
+	total.Duration += time.Since(s.changed)
+	s.totals[s.current] = total
+}
+
 // setError sets the folder state to FolderError with the specified error.
 func (s *stateTracker) setError(err error) {
 	s.mut.Lock()
@@ -102,6 +140,7 @@ func (s *stateTracker) setError(err error) {
 			eventData["duration"] = time.Since(s.changed).Seconds()
 		}
 
+		s.countTime()
 		s.current = FolderError
 		s.err = err
 		s.changed = time.Now()
@@ -125,6 +164,7 @@ func (s *stateTracker) clearError() {
 			eventData["duration"] = time.Since(s.changed).Seconds()
 		}
 
+		s.countTime()
 		s.current = FolderIdle
 		s.err = nil
 		s.changed = time.Now()
@@ -0,0 +1,39 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func TestStateTotals(t *testing.T) {
+	s := stateTracker{
+		folder: "default",
+		mut:    sync.NewMutex(),
+	}
+
+	s.setState(FolderScanning)
+	s.setState(FolderIdle)
+	s.setState(FolderScanning)
+	s.setError(errors.New("failed"))
+	s.clearError()
+	s.setState(FolderSyncing)
+
+	totals := s.getStateTotals()
+	if totals["scanning"].Count != 2 {
+		t.Errorf("expected two scans, got %d", totals["scanning"].Count)
+	}
+	if totals["idle"].Count != 2 || totals["error"].Count != 1 {
+		t.Errorf("unexpected totals %v", totals)
+	}
+	if _, ok := totals["syncing"]; ok {
+		t.Error("the current state should not be counted until left")
+	}
+}
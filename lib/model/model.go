@@ -18,10 +18,12 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	stdsync "sync"
 	"time"
 
+	"github.com/juju/ratelimit"
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/db"
 	"github.com/syncthing/syncthing/lib/events"
@@ -33,7 +35,6 @@ import (
 	"github.com/syncthing/syncthing/lib/symlinks"
 	"github.com/syncthing/syncthing/lib/sync"
 	"github.com/syncthing/syncthing/lib/versioner"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/thejerf/suture"
 )
 
@@ -60,13 +61,15 @@ type service interface {
 	setError(err error)
 	clearError()
 	getState() (folderState, time.Time, error)
+	getStateTotals() map[string]StateTotal
+	stopped() <-chan struct{} // closed when the folder is stopped
 }
 
 type Model struct {
 	*suture.Supervisor
 
 	cfg               *config.Wrapper
-	db                *leveldb.DB
+	db                *db.Instance
 	finder            *db.BlockFinder
 	progressEmitter   *ProgressEmitter
 	id                protocol.DeviceID
@@ -77,15 +80,18 @@ type Model struct {
 	clientName    string
 	clientVersion string
 
-	folderCfgs     map[string]config.FolderConfiguration                  // folder -> cfg
-	folderFiles    map[string]*db.FileSet                                 // folder -> files
-	folderDevices  map[string][]protocol.DeviceID                         // folder -> deviceIDs
-	deviceFolders  map[protocol.DeviceID][]string                         // deviceID -> folders
-	deviceStatRefs map[protocol.DeviceID]*stats.DeviceStatisticsReference // deviceID -> statsRef
-	folderIgnores  map[string]*ignore.Matcher                             // folder -> matcher object
-	folderRunners  map[string]service                                     // folder -> puller or scanner
-	folderStatRefs map[string]*stats.FolderStatisticsReference            // folder -> statsRef
-	fmut           sync.RWMutex                                           // protects the above
+	folderCfgs      map[string]config.FolderConfiguration                  // folder -> cfg
+	folderFiles     map[string]*db.FileSet                                 // folder -> files
+	folderDevices   map[string][]protocol.DeviceID                         // folder -> deviceIDs
+	deviceFolders   map[protocol.DeviceID][]string                         // deviceID -> folders
+	deviceStatRefs  map[protocol.DeviceID]*stats.DeviceStatisticsReference // deviceID -> statsRef
+	folderIgnores   map[string]*ignore.Matcher                             // folder -> matcher object
+	folderRunners   map[string]service                                     // folder -> puller or scanner
+	folderStatRefs  map[string]*stats.FolderStatisticsReference            // folder -> statsRef
+	folderScrubbers map[string]*folderScrubber                             // folder -> scrubber
+	folderRefused   map[string]map[protocol.DeviceID]string                // folder -> deviceID -> reason for not sharing
+	remoteSubs      map[string]map[protocol.DeviceID]subscriptions         // folder -> deviceID -> subtrees the device wants
+	fmut            sync.RWMutex                                           // protects the above
 
 	conn         map[protocol.DeviceID]Connection
 	deviceVer    map[protocol.DeviceID]string
@@ -94,16 +100,25 @@ type Model struct {
 
 	reqValidationCache map[string]time.Time // folder / file name => time when confirmed to exist
 	rvmut              sync.RWMutex         // protects reqValidationCache
+
+	invalidFiles map[string]invalidFilesCache // folder => invalid files as of a local version
+	ifmut        sync.Mutex                   // protects invalidFiles
+
+	hashLimit        *ratelimit.Bucket            // shared by all folders; nil if unlimited
+	folderHashLimits map[string]*ratelimit.Bucket // folder -> own limit, if any; protected by fmut
+	scanSlots        chan struct{}                // one token per concurrently running scan; nil if unlimited
 }
 
 var (
 	symlinkWarning = stdsync.Once{}
 )
 
+var errFolderStopped = errors.New("folder stopped")
+
 // NewModel creates and starts a new model. The model starts in read-only mode,
 // where it sends index information to connected peers and responds to requests
 // for file data without altering the local folder in any way.
-func NewModel(cfg *config.Wrapper, id protocol.DeviceID, deviceName, clientName, clientVersion string, ldb *leveldb.DB) *Model {
+func NewModel(cfg *config.Wrapper, id protocol.DeviceID, deviceName, clientName, clientVersion string, ldb *db.Instance) *Model {
 	m := &Model{
 		Supervisor: suture.New("model", suture.Spec{
 			Log: func(line string) {
@@ -130,14 +145,26 @@ func NewModel(cfg *config.Wrapper, id protocol.DeviceID, deviceName, clientName,
 		folderIgnores:      make(map[string]*ignore.Matcher),
 		folderRunners:      make(map[string]service),
 		folderStatRefs:     make(map[string]*stats.FolderStatisticsReference),
+		folderScrubbers:    make(map[string]*folderScrubber),
+		folderRefused:      make(map[string]map[protocol.DeviceID]string),
+		remoteSubs:         make(map[string]map[protocol.DeviceID]subscriptions),
 		conn:               make(map[protocol.DeviceID]Connection),
 		deviceVer:          make(map[protocol.DeviceID]string),
 		devicePaused:       make(map[protocol.DeviceID]bool),
 		reqValidationCache: make(map[string]time.Time),
+		folderHashLimits:   make(map[string]*ratelimit.Bucket),
+		invalidFiles:       make(map[string]invalidFilesCache),
 
 		fmut:  sync.NewRWMutex(),
 		pmut:  sync.NewRWMutex(),
 		rvmut: sync.NewRWMutex(),
+		ifmut: sync.NewMutex(),
+	}
+	if opts := cfg.Options(); opts.MaxHashMBps > 0 {
+		m.hashLimit = newHashLimit(opts.MaxHashMBps)
+	}
+	if opts := cfg.Options(); opts.MaxConcurrentScans > 0 {
+		m.scanSlots = make(chan struct{}, opts.MaxConcurrentScans)
 	}
 	if cfg.Options().ProgressUpdateIntervalS > -1 {
 		go m.progressEmitter.Serve()
@@ -190,6 +217,7 @@ func (m *Model) StartFolderRW(folder string) {
 	}
 
 	m.Add(p)
+	m.startScrubber(cfg)
 
 	l.Okln("Ready to synchronize", folder, "(read-write)")
 }
@@ -213,10 +241,61 @@ func (m *Model) StartFolderRO(folder string) {
 	m.fmut.Unlock()
 
 	m.Add(s)
+	m.startScrubber(cfg)
 
 	l.Okln("Ready to synchronize", folder, "(read only; no external updates accepted)")
 }
 
+// startScrubber starts the periodic integrity scrub of the folder, if
+// enabled in the configuration.
+func (m *Model) startScrubber(cfg config.FolderConfiguration) {
+	if cfg.ScrubIntervalH <= 0 {
+		return
+	}
+
+	s := newFolderScrubber(m, cfg)
+	m.fmut.Lock()
+	m.folderScrubbers[cfg.ID] = s
+	m.fmut.Unlock()
+
+	m.Add(s)
+}
+
+// ScrubStatus returns the status of the latest integrity scrub of the folder.
+func (m *Model) ScrubStatus(folder string) (ScrubStatus, error) {
+	m.fmut.RLock()
+	s, ok := m.folderScrubbers[folder]
+	m.fmut.RUnlock()
+	if !ok {
+		return ScrubStatus{}, errScrubDisabled
+	}
+	return s.Status(), nil
+}
+
+// ScrubFolder runs an integrity scrub of the folder immediately and returns
+// when it has completed.
+func (m *Model) ScrubFolder(folder string) error {
+	m.fmut.RLock()
+	s, ok := m.folderScrubbers[folder]
+	m.fmut.RUnlock()
+	if !ok {
+		return errScrubDisabled
+	}
+	return s.Scrub()
+}
+
+// TriggerScrub schedules an integrity scrub of the folder to start as soon
+// as possible, and returns without waiting for it.
+func (m *Model) TriggerScrub(folder string) error {
+	m.fmut.RLock()
+	s, ok := m.folderScrubbers[folder]
+	m.fmut.RUnlock()
+	if !ok {
+		return errScrubDisabled
+	}
+	return s.Trigger()
+}
+
 type ConnectionInfo struct {
 	protocol.Statistics
 	Connected     bool
@@ -310,13 +389,15 @@ func (m *Model) Completion(device protocol.DeviceID, folder string) float64 {
 
 	m.fmut.RLock()
 	rf, ok := m.folderFiles[folder]
+	subs := m.remoteSubs[folder][device]
 	m.fmut.RUnlock()
 	if !ok {
 		return 0 // Folder doesn't exist, so we hardly have any of it
 	}
 
+	// Only the subtrees the device subscribes to count.
 	rf.WithGlobalTruncated(func(f db.FileIntf) bool {
-		if !f.IsDeleted() {
+		if !f.IsDeleted() && subs.covers(f.(db.FileInfoTruncated).Name) {
 			tot += f.Size()
 		}
 		return true
@@ -328,7 +409,7 @@ func (m *Model) Completion(device protocol.DeviceID, folder string) float64 {
 
 	var need int64
 	rf.WithNeedTruncated(device, func(f db.FileIntf) bool {
-		if !f.IsDeleted() {
+		if !f.IsDeleted() && subs.covers(f.(db.FileInfoTruncated).Name) {
 			need += f.Size()
 		}
 		return true
@@ -404,7 +485,11 @@ func (m *Model) NeedSize(folder string) (nfiles int, bytes int64) {
 	m.fmut.RLock()
 	defer m.fmut.RUnlock()
 	if rf, ok := m.folderFiles[folder]; ok {
+		subs := newSubscriptions(m.folderCfgs[folder].Subscriptions)
 		rf.WithNeedTruncated(protocol.LocalDeviceID, func(f db.FileIntf) bool {
+			if !subs.covers(f.(db.FileInfoTruncated).Name) {
+				return true
+			}
 			fs, de, by := sizeOfFile(f)
 			nfiles += fs + de
 			bytes += by
@@ -465,8 +550,12 @@ func (m *Model) NeedFolderFiles(folder string, page, perpage int) ([]db.FileInfo
 		}
 	}
 
+	subs := newSubscriptions(m.folderCfgs[folder].Subscriptions)
 	rest = make([]db.FileInfoTruncated, 0, perpage)
 	rf.WithNeedTruncated(protocol.LocalDeviceID, func(f db.FileIntf) bool {
+		if !subs.covers(f.(db.FileInfoTruncated).Name) {
+			return true
+		}
 		total++
 		if skip > 0 {
 			skip--
@@ -576,6 +665,9 @@ func (m *Model) IndexUpdate(deviceID protocol.DeviceID, folder string, fs []prot
 func (m *Model) folderSharedWith(folder string, deviceID protocol.DeviceID) bool {
 	m.fmut.RLock()
 	defer m.fmut.RUnlock()
+	if _, ok := m.folderRefused[folder][deviceID]; ok {
+		return false
+	}
 	for _, nfolder := range m.deviceFolders[deviceID] {
 		if nfolder == folder {
 			return true
@@ -586,6 +678,9 @@ func (m *Model) folderSharedWith(folder string, deviceID protocol.DeviceID) bool
 
 func (m *Model) ClusterConfig(deviceID protocol.DeviceID, cm protocol.ClusterConfigMessage) {
 	m.pmut.Lock()
+	// A cluster config is sent again on an existing connection when the
+	// other device's subscriptions change.
+	_, reannounce := m.deviceVer[deviceID]
 	if cm.ClientName == "syncthing" {
 		m.deviceVer[deviceID] = cm.ClientVersion
 	} else {
@@ -608,9 +703,12 @@ func (m *Model) ClusterConfig(deviceID protocol.DeviceID, cm protocol.ClusterCon
 
 	m.pmut.Unlock()
 
-	events.Default.Log(events.DeviceConnected, event)
+	if !reannounce {
+		events.Default.Log(events.DeviceConnected, event)
+		l.Infof(`Device %s client is "%s %s"`, deviceID, cm.ClientName, cm.ClientVersion)
+	}
 
-	l.Infof(`Device %s client is "%s %s"`, deviceID, cm.ClientName, cm.ClientVersion)
+	m.setRemoteSubscriptions(deviceID, cm)
 
 	var changed bool
 
@@ -624,6 +722,37 @@ func (m *Model) ClusterConfig(deviceID protocol.DeviceID, cm protocol.ClusterCon
 		}
 	}
 
+	refused, resumed := m.checkHashAlgorithms(deviceID, cm)
+
+	m.pmut.RLock()
+	if conn, ok := m.conn[deviceID]; ok {
+		if !reannounce {
+			// We know which folders we can share with the device now, so
+			// start sending indexes for them.
+			m.fmut.RLock()
+			for _, folder := range m.deviceFolders[deviceID] {
+				if _, ok := m.folderRefused[folder][deviceID]; ok {
+					continue
+				}
+				go m.sendIndexes(conn, folder, m.folderFiles[folder], m.folderIgnores[folder])
+			}
+			m.fmut.RUnlock()
+		}
+
+		switch {
+		case resumed && reannounce:
+			// Sharing a folder again needs a fresh exchange of indexes. We
+			// close the underlying connection and let the normal error
+			// handling kick in to clean up and reconnect.
+			closeRawConn(conn)
+		case refused:
+			// Our cluster config announced the folders before we knew
+			// that we'd refuse some of them.
+			conn.ClusterConfig(m.clusterConfig(deviceID))
+		}
+	}
+	m.pmut.RUnlock()
+
 	if m.cfg.Devices()[deviceID].Introducer {
 		// This device is an introducer. Go through the announced lists of folders
 		// and devices and add what we are missing.
@@ -689,8 +818,156 @@ func (m *Model) ClusterConfig(deviceID protocol.DeviceID, cm protocol.ClusterCon
 	}
 
 	if changed {
-		m.cfg.Save()
+		m.cfg.SaveFrom(config.ChangeSource{Type: config.SourceIntroducer, Name: deviceID.String()})
+	}
+}
+
+// setRemoteSubscriptions records the subtrees the device wants of each folder
+// we share with it.
+func (m *Model) setRemoteSubscriptions(deviceID protocol.DeviceID, cm protocol.ClusterConfigMessage) {
+	m.fmut.Lock()
+	defer m.fmut.Unlock()
+
+	shared := make(map[string]bool)
+	for _, folder := range m.deviceFolders[deviceID] {
+		shared[folder] = true
+	}
+
+	for _, folder := range cm.Folders {
+		if !shared[folder.ID] {
+			continue
+		}
+		if m.remoteSubs[folder.ID] == nil {
+			m.remoteSubs[folder.ID] = make(map[protocol.DeviceID]subscriptions)
+		}
+		m.remoteSubs[folder.ID][deviceID] = subscriptionsFromOptions(folder)
+	}
+}
+
+// Subscriptions returns the subtrees of the folder this device syncs, and
+// those that each other device sharing it has announced. An empty list means
+// the whole folder.
+func (m *Model) Subscriptions(folder string) ([]string, map[protocol.DeviceID][]string, error) {
+	m.fmut.RLock()
+	defer m.fmut.RUnlock()
+
+	cfg, ok := m.folderCfgs[folder]
+	if !ok {
+		return nil, nil, fmt.Errorf("Folder %s does not exist", folder)
+	}
+
+	remote := make(map[protocol.DeviceID][]string)
+	for device, subs := range m.remoteSubs[folder] {
+		paths := make([]string, len(subs))
+		for i, sub := range subs {
+			paths[i] = filepath.ToSlash(sub)
+		}
+		remote[device] = paths
+	}
+
+	return cfg.Subscriptions, remote, nil
+}
+
+// SetSubscriptions changes the subtrees of the folder this device syncs.
+// Other devices are told, and files in newly subscribed subtrees are pulled
+// from the existing index.
+func (m *Model) SetSubscriptions(folder string, paths []string) error {
+	cfg, ok := m.cfg.Folders()[folder]
+	if !ok {
+		return fmt.Errorf("Folder %s does not exist", folder)
+	}
+
+	cfg.Subscriptions = paths
+	m.cfg.SetFolder(cfg)
+	return m.cfg.Save()
+}
+
+// applySubscriptions makes a change to the subscriptions of a running folder
+// take effect.
+func (m *Model) applySubscriptions(folder string, paths []string) {
+	m.fmut.Lock()
+	cfg := m.folderCfgs[folder]
+	cfg.Subscriptions = paths
+	m.folderCfgs[folder] = cfg
+	m.fmut.Unlock()
+
+	// We're called while the configuration is being committed, and
+	// creating the cluster config requires looking at it.
+	go m.announceSubscriptions(folder)
+}
+
+// announceSubscriptions sends a new cluster config to the connected devices
+// sharing the folder, and has the puller evaluate the needed files again.
+func (m *Model) announceSubscriptions(folder string) {
+	m.fmut.RLock()
+	runner := m.folderRunners[folder]
+	devices := m.folderDevices[folder]
+	m.fmut.RUnlock()
+
+	m.pmut.RLock()
+	for _, device := range devices {
+		if conn, ok := m.conn[device]; ok {
+			conn.ClusterConfig(m.clusterConfig(device))
+		}
+	}
+	m.pmut.RUnlock()
+
+	if runner != nil {
+		runner.IndexUpdated()
+	}
+}
+
+// checkHashAlgorithms refuses to share folders with the device when either
+// side uses a hash algorithm for the folder that the other side can't
+// verify. Devices not announcing their supported algorithms only support
+// SHA-256. It returns whether any folder was refused or became shared again.
+func (m *Model) checkHashAlgorithms(deviceID protocol.DeviceID, cm protocol.ClusterConfigMessage) (refused, resumed bool) {
+	supported := make(map[protocol.HashAlgorithm]bool)
+	for _, algo := range protocol.ParseHashAlgorithms(cm.GetOption("hashAlgorithms")) {
+		supported[algo] = true
 	}
+	remoteAlgos := make(map[string]string, len(cm.Folders))
+	for _, folder := range cm.Folders {
+		remoteAlgos[folder.ID] = folder.GetOption("hashAlgorithm")
+	}
+
+	m.fmut.Lock()
+	defer m.fmut.Unlock()
+
+	for _, folder := range m.deviceFolders[deviceID] {
+		var reason string
+		if algo := m.folderCfgs[folder].HashAlgorithm; !supported[algo] {
+			reason = fmt.Sprintf("device does not support hash algorithm %v", algo)
+		} else if name := remoteAlgos[folder]; name != "" && len(protocol.ParseHashAlgorithms(name)) == 0 {
+			reason = fmt.Sprintf("device uses unsupported hash algorithm %q", name)
+		}
+
+		_, wasRefused := m.folderRefused[folder][deviceID]
+		if reason == "" {
+			if wasRefused {
+				delete(m.folderRefused[folder], deviceID)
+				resumed = true
+			}
+			continue
+		}
+		refused = true
+
+		l.Warnf("Not sharing folder %q with device %v: %s", folder, deviceID, reason)
+		events.Default.Log(events.FolderRejected, map[string]string{
+			"folder": folder,
+			"device": deviceID.String(),
+			"reason": reason,
+		})
+
+		if m.folderRefused[folder] == nil {
+			m.folderRefused[folder] = make(map[protocol.DeviceID]string)
+		}
+		m.folderRefused[folder][deviceID] = reason
+		if files, ok := m.folderFiles[folder]; ok {
+			files.Replace(deviceID, nil)
+		}
+	}
+	return refused, resumed
 }
 
 // Close removes the peer from the model and closes the underlying connection if possible.
@@ -809,6 +1086,16 @@ func (m *Model) Request(deviceID protocol.DeviceID, folder, name string, offset
 	if debug && deviceID != protocol.LocalDeviceID {
 		l.Debugf("%v REQ(in): %s: %q / %q o=%d s=%d", m, deviceID, folder, name, offset, len(buf))
 	}
+
+	if scanner.IsZeroBlockHash(hash, len(buf)) {
+		// The requester wants a block of zeros, whatever is on disk. No
+		// need to read it.
+		for i := range buf {
+			buf[i] = 0
+		}
+		return nil
+	}
+
 	m.fmut.RLock()
 	fn := filepath.Join(m.folderCfgs[folder].Path(), name)
 	m.fmut.RUnlock()
@@ -840,6 +1127,46 @@ func (m *Model) Request(deviceID protocol.DeviceID, folder, name string, offset
 	return nil
 }
 
+type invalidFilesCache struct {
+	localVersion int64
+	files        []fileError
+}
+
+// InvalidFiles returns the files in the folder that are not synced for some
+// other reason than being ignored, such as having a name unusable on one of
+// the folder's target platforms or being too large, with the reason for each.
+// The list is only rebuilt when the local index has changed.
+func (m *Model) InvalidFiles(folder string) []fileError {
+	m.fmut.RLock()
+	rf, ok := m.folderFiles[folder]
+	m.fmut.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	localVersion := rf.LocalVersion(protocol.LocalDeviceID)
+	m.ifmut.Lock()
+	cached, ok := m.invalidFiles[folder]
+	m.ifmut.Unlock()
+	if ok && cached.localVersion == localVersion {
+		return cached.files
+	}
+
+	res := []fileError{}
+	rf.WithHaveTruncated(protocol.LocalDeviceID, func(fi db.FileIntf) bool {
+		f := fi.(db.FileInfoTruncated)
+		if reason := f.InvalidReason(); f.IsInvalid() && !f.IsDeleted() && reason != protocol.InvalidReasonNone {
+			res = append(res, fileError{f.Name, reason.String()})
+		}
+		return true
+	})
+
+	m.ifmut.Lock()
+	m.invalidFiles[folder] = invalidFilesCache{localVersion, res}
+	m.ifmut.Unlock()
+	return res
+}
+
 func (m *Model) CurrentFolderFile(folder string, file string) (protocol.FileInfo, bool) {
 	m.fmut.RLock()
 	fs, ok := m.folderFiles[folder]
@@ -915,6 +1242,27 @@ func (m *Model) GetIgnores(folder string) ([]string, []string, error) {
 	return lines, patterns, nil
 }
 
+// ExplainIgnore returns the ignore pattern deciding whether the given file in
+// the folder is ignored. Whether it's a directory is taken from our index
+// entry for it, or else from the global one.
+func (m *Model) ExplainIgnore(folder, file string) (ignore.Explanation, error) {
+	m.fmut.RLock()
+	defer m.fmut.RUnlock()
+
+	if _, ok := m.folderCfgs[folder]; !ok {
+		return ignore.Explanation{}, fmt.Errorf("Folder %s does not exist", folder)
+	}
+
+	name := filepath.FromSlash(file)
+	fs := m.folderFiles[folder]
+	f, ok := fs.Get(protocol.LocalDeviceID, name)
+	if !ok {
+		f, _ = fs.GetGlobal(name)
+	}
+
+	return m.folderIgnores[folder].Explain(name, f.IsDirectory()), nil
+}
+
 func (m *Model) SetIgnores(folder string, content []string) error {
 	cfg, ok := m.folderCfgs[folder]
 	if !ok {
@@ -959,12 +1307,8 @@ func (m *Model) AddConnection(conn Connection) {
 	cm := m.clusterConfig(deviceID)
 	conn.ClusterConfig(cm)
 
-	m.fmut.RLock()
-	for _, folder := range m.deviceFolders[deviceID] {
-		fs := m.folderFiles[folder]
-		go sendIndexes(conn, folder, fs, m.folderIgnores[folder])
-	}
-	m.fmut.RUnlock()
+	// Indexes are sent once the device's cluster config has told us which
+	// folders we can share with it.
 	m.pmut.Unlock()
 
 	m.deviceWasSeen(deviceID)
@@ -1028,7 +1372,7 @@ func (m *Model) receivedFile(folder string, file protocol.FileInfo) {
 	m.folderStatRef(folder).ReceivedFile(file.Name, file.IsDeleted())
 }
 
-func sendIndexes(conn protocol.Connection, folder string, fs *db.FileSet, ignores *ignore.Matcher) {
+func (m *Model) sendIndexes(conn protocol.Connection, folder string, fs *db.FileSet, ignores *ignore.Matcher) {
 	deviceID := conn.ID()
 	name := conn.Name()
 	var err error
@@ -1043,6 +1387,12 @@ func sendIndexes(conn protocol.Connection, folder string, fs *db.FileSet, ignore
 	defer events.Default.Unsubscribe(sub)
 
 	for err == nil {
+		// The folder may be refused when the device announces a new cluster
+		// config.
+		if !m.folderSharedWith(folder, deviceID) {
+			break
+		}
+
 		// While we have sent a localVersion at least equal to the one
 		// currently in the database, wait for the local index to update. The
 		// local index may update for other folders than the one we are
@@ -1083,7 +1433,7 @@ func sendIndexTo(initial bool, minLocalVer int64, conn protocol.Connection, fold
 			maxLocalVer = f.LocalVersion
 		}
 
-		if ignores.Match(f.Name) || symlinkInvalid(folder, f) {
+		if ignores.MatchFile(f.Name, f.IsDirectory()) || symlinkInvalid(folder, f) {
 			if debug {
 				l.Debugln("not sending update for ignored/unsupported symlink", f)
 			}
@@ -1186,6 +1536,10 @@ func (m *Model) AddFolder(cfg config.FolderConfiguration) {
 	_ = ignores.Load(filepath.Join(cfg.Path(), ".stignore")) // Ignore error, there might not be an .stignore
 	m.folderIgnores[cfg.ID] = ignores
 
+	if cfg.MaxHashMBps > 0 {
+		m.folderHashLimits[cfg.ID] = newHashLimit(cfg.MaxHashMBps)
+	}
+
 	m.fmut.Unlock()
 }
 
@@ -1260,6 +1614,7 @@ func (m *Model) internalScanFolderSubs(folder string, subs []string) error {
 	folderCfg := m.folderCfgs[folder]
 	ignores := m.folderIgnores[folder]
 	runner, ok := m.folderRunners[folder]
+	hashLimit := m.folderHashLimits[folder]
 	m.fmut.Unlock()
 
 	// Folders are added to folderRunners only when they are started. We can't
@@ -1305,16 +1660,45 @@ nextSub:
 		Subs:                  subs,
 		Matcher:               ignores,
 		BlockSize:             protocol.BlockSize,
+		HashAlgorithm:         folderCfg.HashAlgorithm,
 		TempNamer:             defTempNamer,
 		TempLifetime:          time.Duration(m.cfg.Options().KeepTemporariesH) * time.Hour,
 		CurrentFiler:          cFiler{m, folder},
 		MtimeRepo:             db.NewVirtualMtimeRepo(m.db, folderCfg.ID),
+		HashCheckpoints:       db.NewHashCheckpointRepo(m.db, folderCfg.ID),
+		InodeRepo:             db.NewInodeRepo(m.db, folderCfg.ID),
+		TargetPlatforms:       folderCfg.TargetPlatforms,
+		MaxFileSize:           folderCfg.IgnoreLargerThanMiB << 20,
+		MinFileAge:            time.Duration(folderCfg.IgnoreModifiedWithinS) * time.Second,
 		IgnorePerms:           folderCfg.IgnorePerms,
 		AutoNormalize:         folderCfg.AutoNormalize,
 		Hashers:               m.numHashers(folder),
 		ShortID:               m.shortID,
 		ProgressTickIntervalS: folderCfg.ScanProgressIntervalS,
 	}
+	if m.hashLimit != nil {
+		w.RateLimits = append(w.RateLimits, m.hashLimit)
+	}
+	if hashLimit != nil {
+		w.RateLimits = append(w.RateLimits, hashLimit)
+	}
+
+	if m.scanSlots != nil {
+		select {
+		case m.scanSlots <- struct{}{}:
+		default:
+			// Too many other folders are scanning; wait our turn.
+			runner.setState(FolderScanWaiting)
+			select {
+			case m.scanSlots <- struct{}{}:
+			case <-runner.stopped():
+				return errFolderStopped
+			}
+		}
+		defer func() {
+			<-m.scanSlots
+		}()
+	}
 
 	runner.setState(FolderScanning)
 
@@ -1335,8 +1719,12 @@ nextSub:
 
 	batch := make([]protocol.FileInfo, 0, batchSizeFiles)
 	blocksHandled := 0
+	var invalidFiles []fileError
 
 	for f := range fchan {
+		if reason := f.InvalidReason(); f.IsInvalid() && reason != protocol.InvalidReasonNone {
+			invalidFiles = append(invalidFiles, fileError{f.Name, reason.String()})
+		}
 		if len(batch) == batchSizeFiles || blocksHandled > batchSizeBlocks {
 			if err := m.CheckFolderHealth(folder); err != nil {
 				l.Infof("Stopping folder %s mid-scan due to folder error: %s", folder, err)
@@ -1357,6 +1745,14 @@ nextSub:
 		m.updateLocals(folder, batch)
 	}
 
+	if len(invalidFiles) > 0 {
+		sort.Sort(fileErrorList(invalidFiles))
+		events.Default.Log(events.FolderErrors, map[string]interface{}{
+			"folder": folder,
+			"errors": invalidFiles,
+		})
+	}
+
 	batch = batch[:0]
 	// TODO: We should limit the Have scanning to start at sub
 	seenPrefix := false
@@ -1379,7 +1775,10 @@ nextSub:
 
 		seenPrefix = true
 		if !f.IsDeleted() {
-			if f.IsInvalid() {
+			if f.IsInvalid() && (f.InvalidReason() == protocol.InvalidReasonNone || ignores.MatchFile(f.Name, f.IsDirectory())) {
+				// Ignored files are left alone. Files invalid because of
+				// their names or attributes are checked for deletion like
+				// any other.
 				return true
 			}
 
@@ -1392,7 +1791,7 @@ nextSub:
 				batch = batch[:0]
 			}
 
-			if ignores.Match(f.Name) || symlinkInvalid(folder, f) {
+			if ignores.MatchFile(f.Name, f.IsDirectory()) || symlinkInvalid(folder, f) {
 				// File has been ignored or an unsupported symlink. Set invalid bit.
 				if debug {
 					l.Debugln("setting invalid bit on ignored", f)
@@ -1481,6 +1880,28 @@ func (m *Model) numHashers(folder string) int {
 	return 1
 }
 
+// setFolderHashLimit replaces the hashing rate limit of the folder, taking
+// effect from the next scan.
+func (m *Model) setFolderHashLimit(folder string, mbps int) {
+	m.fmut.Lock()
+	cfg := m.folderCfgs[folder]
+	cfg.MaxHashMBps = mbps
+	m.folderCfgs[folder] = cfg
+	if mbps > 0 {
+		m.folderHashLimits[folder] = newHashLimit(mbps)
+	} else {
+		delete(m.folderHashLimits, folder)
+	}
+	m.fmut.Unlock()
+}
+
+// newHashLimit returns a rate limit bucket for hashing at the given number of
+// megabytes per second, allowing bursts of up to one second's worth.
+func newHashLimit(mbps int) *ratelimit.Bucket {
+	rate := int64(mbps) * 1000 * 1000
+	return ratelimit.NewBucketWithRate(float64(rate), rate)
+}
+
 // clusterConfig returns a ClusterConfigMessage that is correct for the given peer device
 func (m *Model) clusterConfig(device protocol.DeviceID) protocol.ClusterConfigMessage {
 	cm := protocol.ClusterConfigMessage{
@@ -1491,14 +1912,28 @@ func (m *Model) clusterConfig(device protocol.DeviceID) protocol.ClusterConfigMe
 				Key:   "name",
 				Value: m.deviceName,
 			},
+			{
+				Key:   "hashAlgorithms",
+				Value: protocol.FormatHashAlgorithms(protocol.HashAlgorithms),
+			},
 		},
 	}
 
 	m.fmut.RLock()
 	for _, folder := range m.deviceFolders[device] {
+		if _, ok := m.folderRefused[folder][device]; ok {
+			continue
+		}
 		cr := protocol.Folder{
 			ID: folder,
+			Options: []protocol.Option{
+				{
+					Key:   "hashAlgorithm",
+					Value: m.folderCfgs[folder].HashAlgorithm.String(),
+				},
+			},
 		}
+		cr.Options = append(cr.Options, newSubscriptions(m.folderCfgs[folder].Subscriptions).options()...)
 		for _, device := range m.folderDevices[folder] {
 			// DeviceID is a value type, but with an underlying array. Copy it
 			// so we don't grab aliases to the same array later on in device[:]
@@ -1534,6 +1969,18 @@ func (m *Model) State(folder string) (string, time.Time, error) {
 	return state.String(), changed, err
 }
 
+// StateTotals returns how many times, and for how long in total, the folder
+// has been in each state.
+func (m *Model) StateTotals(folder string) map[string]StateTotal {
+	m.fmut.RLock()
+	runner, ok := m.folderRunners[folder]
+	m.fmut.RUnlock()
+	if !ok {
+		return nil
+	}
+	return runner.getStateTotals()
+}
+
 func (m *Model) Override(folder string) {
 	m.fmut.RLock()
 	fs, ok := m.folderFiles[folder]
@@ -1882,9 +2329,24 @@ func (m *Model) CommitConfiguration(from, to config.Configuration) bool {
 			}
 		}
 
-		// Check if anything else differs, apart from the device list.
+		// Subscription changes are applied without a restart.
+		if !reflect.DeepEqual(fromCfg.Subscriptions, toCfg.Subscriptions) {
+			m.applySubscriptions(folderID, toCfg.Subscriptions)
+		}
+
+		// So are changes to the hashing rate limit.
+		if fromCfg.MaxHashMBps != toCfg.MaxHashMBps {
+			m.setFolderHashLimit(folderID, toCfg.MaxHashMBps)
+		}
+
+		// Check if anything else differs, apart from the device list,
+		// subscriptions and rate limit.
 		fromCfg.Devices = nil
 		toCfg.Devices = nil
+		fromCfg.Subscriptions = nil
+		toCfg.Subscriptions = nil
+		fromCfg.MaxHashMBps = 0
+		toCfg.MaxHashMBps = 0
 		if !reflect.DeepEqual(fromCfg, toCfg) {
 			if debug {
 				l.Debugln(m, "requires restart, folder", folderID, "configuration differs")
@@ -1953,6 +2415,12 @@ func filterIndex(folder string, fs []protocol.FileInfo, dropDeletes bool) []prot
 			}
 			fs[i] = fs[len(fs)-1]
 			fs = fs[:len(fs)-1]
+		} else if !fs[i].HashAlgorithm().IsKnown() {
+			if debug {
+				l.Debugln("dropping update for file with unknown hash algorithm", fs[i])
+			}
+			fs[i] = fs[len(fs)-1]
+			fs = fs[:len(fs)-1]
 		} else if fs[i].IsDeleted() && dropDeletes {
 			if debug {
 				l.Debugln("dropping update for undesired delete", fs[i])
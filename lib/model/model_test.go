@@ -16,14 +16,13 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/db"
 	"github.com/syncthing/syncthing/lib/protocol"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/storage"
 )
 
 var device1, device2 protocol.DeviceID
@@ -90,7 +89,7 @@ func init() {
 }
 
 func TestRequest(t *testing.T) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 
@@ -167,7 +166,7 @@ func BenchmarkIndex_100(b *testing.B) {
 }
 
 func benchmarkIndex(b *testing.B, nfiles int) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.StartFolderRO("default")
@@ -196,7 +195,7 @@ func BenchmarkIndexUpdate_10000_1(b *testing.B) {
 }
 
 func benchmarkIndexUpdate(b *testing.B, nfiles, nufiles int) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.StartFolderRO("default")
@@ -261,7 +260,7 @@ func (FakeConnection) Statistics() protocol.Statistics {
 }
 
 func BenchmarkRequest(b *testing.B) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.ServeBackground()
@@ -308,6 +307,7 @@ func TestDeviceRename(t *testing.T) {
 	}
 
 	defer os.Remove("tmpconfig.xml")
+	defer os.RemoveAll("config-history")
 
 	rawCfg := config.New(device1)
 	rawCfg.Devices = []config.DeviceConfiguration{
@@ -317,7 +317,7 @@ func TestDeviceRename(t *testing.T) {
 	}
 	cfg := config.Wrap("tmpconfig.xml", rawCfg)
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 
 	fc := FakeConnection{
@@ -396,7 +396,7 @@ func TestClusterConfig(t *testing.T) {
 		},
 	}
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 
 	m := NewModel(config.Wrap("/tmp/test", cfg), protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(cfg.Folders[0])
@@ -450,6 +450,188 @@ func TestClusterConfig(t *testing.T) {
 	}
 }
 
+func TestClusterConfigHashAlgorithm(t *testing.T) {
+	cfg := config.New(device1)
+	cfg.Devices = []config.DeviceConfiguration{
+		{
+			DeviceID: device1,
+		},
+	}
+	cfg.Folders = []config.FolderConfiguration{
+		{
+			ID:            "sha",
+			Devices:       []config.FolderDeviceConfiguration{{DeviceID: device1}},
+			HashAlgorithm: protocol.SHA256,
+		},
+		{
+			ID:            "blake",
+			Devices:       []config.FolderDeviceConfiguration{{DeviceID: device1}},
+			HashAlgorithm: protocol.BLAKE2b,
+		},
+	}
+
+	db := db.OpenMemory()
+	m := NewModel(config.Wrap("/tmp/test", cfg), protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(cfg.Folders[0])
+	m.AddFolder(cfg.Folders[1])
+
+	cm := m.clusterConfig(device1)
+	if algos := protocol.ParseHashAlgorithms(cm.GetOption("hashAlgorithms")); len(algos) != len(protocol.HashAlgorithms) {
+		t.Errorf("Incorrect advertised hash algorithms %v", algos)
+	}
+	if algo := cm.Folders[1].GetOption("hashAlgorithm"); algo != "blake2b" {
+		t.Errorf("Incorrect folder hash algorithm %q != blake2b", algo)
+	}
+
+	// A device not announcing any algorithms only supports SHA-256
+	m.ClusterConfig(device1, protocol.ClusterConfigMessage{})
+	if !m.folderSharedWith("sha", device1) {
+		t.Error("SHA-256 folder should be shared")
+	}
+	if m.folderSharedWith("blake", device1) {
+		t.Error("BLAKE2b folder should not be shared with a device not supporting it")
+	}
+
+	// Sharing resumes once the device announces support
+	m.ClusterConfig(device1, protocol.ClusterConfigMessage{
+		Options: []protocol.Option{{Key: "hashAlgorithms", Value: "sha256,blake2b"}},
+	})
+	if !m.folderSharedWith("blake", device1) {
+		t.Error("BLAKE2b folder should be shared")
+	}
+
+	// A folder using an algorithm we don't know is refused
+	m.ClusterConfig(device1, protocol.ClusterConfigMessage{
+		Folders: []protocol.Folder{
+			{
+				ID:      "sha",
+				Options: []protocol.Option{{Key: "hashAlgorithm", Value: "md5"}},
+			},
+		},
+		Options: []protocol.Option{{Key: "hashAlgorithms", Value: "sha256,blake2b"}},
+	})
+	if m.folderSharedWith("sha", device1) {
+		t.Error("Folder using an unknown algorithm should not be shared")
+	}
+}
+
+// recordingConnection records the cluster configs and the folders of the
+// indexes sent to it.
+type recordingConnection struct {
+	FakeConnection
+	mut     sync.Mutex
+	configs []protocol.ClusterConfigMessage
+	indexed []string
+}
+
+func (c *recordingConnection) ClusterConfig(cm protocol.ClusterConfigMessage) {
+	c.mut.Lock()
+	c.configs = append(c.configs, cm)
+	c.mut.Unlock()
+}
+
+func (c *recordingConnection) Index(folder string, _ []protocol.FileInfo, _ uint32, _ []protocol.Option) error {
+	c.mut.Lock()
+	c.indexed = append(c.indexed, folder)
+	c.mut.Unlock()
+	return nil
+}
+
+func TestClusterConfigRefusedFolder(t *testing.T) {
+	cfg := config.New(device1)
+	cfg.Devices = []config.DeviceConfiguration{
+		{
+			DeviceID: device1,
+		},
+	}
+	cfg.Folders = []config.FolderConfiguration{
+		{
+			ID:            "sha",
+			Devices:       []config.FolderDeviceConfiguration{{DeviceID: device1}},
+			HashAlgorithm: protocol.SHA256,
+		},
+		{
+			ID:            "blake",
+			Devices:       []config.FolderDeviceConfiguration{{DeviceID: device1}},
+			HashAlgorithm: protocol.BLAKE2b,
+		},
+	}
+
+	db := db.OpenMemory()
+	m := NewModel(config.Wrap("/tmp/test", cfg), protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(cfg.Folders[0])
+	m.AddFolder(cfg.Folders[1])
+
+	conn := &recordingConnection{FakeConnection: FakeConnection{id: device1}}
+	m.AddConnection(Connection{&net.TCPConn{}, conn, ConnectionTypeDirectAccept})
+
+	// A device not announcing any algorithms only supports SHA-256
+	m.ClusterConfig(device1, protocol.ClusterConfigMessage{})
+
+	conn.mut.Lock()
+	configs := conn.configs
+	conn.mut.Unlock()
+	if len(configs) != 2 {
+		t.Fatalf("Expected two cluster configs, got %d", len(configs))
+	}
+	if folders := configs[1].Folders; len(folders) != 1 || folders[0].ID != "sha" {
+		t.Errorf("Refused folder announced in %+v", folders)
+	}
+
+	// Give the index senders time to run.
+	time.Sleep(250 * time.Millisecond)
+	conn.mut.Lock()
+	indexed := append([]string(nil), conn.indexed...)
+	conn.mut.Unlock()
+	if len(indexed) != 1 || indexed[0] != "sha" {
+		t.Errorf("Incorrect folders indexed %v", indexed)
+	}
+}
+
+func TestSubscriptionCompletion(t *testing.T) {
+	db := db.OpenMemory()
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(defaultFolderConfig)
+
+	var version protocol.Vector
+	version = version.Update(1)
+	file := func(name string) protocol.FileInfo {
+		return protocol.FileInfo{
+			Name:    name,
+			Version: version,
+			Blocks:  []protocol.BlockInfo{{Size: 1000, Hash: []byte("hash")}},
+		}
+	}
+	files := []protocol.FileInfo{file(filepath.Join("alpha", "file")), file(filepath.Join("beta", "file"))}
+	m.updateLocals("default", files)
+
+	if c := m.Completion(device1, "default"); c != 0 {
+		t.Errorf("Incorrect completion %v != 0", c)
+	}
+
+	m.Index(device1, "default", files[:1], 0, nil)
+	if c := m.Completion(device1, "default"); c != 50 {
+		t.Errorf("Incorrect completion %v != 50", c)
+	}
+
+	// Once the device announces it only wants alpha, it has everything.
+	m.ClusterConfig(device1, protocol.ClusterConfigMessage{
+		Folders: []protocol.Folder{
+			{
+				ID:      "default",
+				Options: []protocol.Option{{Key: "subscription", Value: "alpha"}},
+			},
+		},
+	})
+	if c := m.Completion(device1, "default"); c != 100 {
+		t.Errorf("Incorrect completion %v != 100", c)
+	}
+
+	if _, remote, err := m.Subscriptions("default"); err != nil || len(remote[device1]) != 1 || remote[device1][0] != "alpha" {
+		t.Errorf("Incorrect remote subscriptions %v, %v", remote, err)
+	}
+}
+
 func TestIgnores(t *testing.T) {
 	arrEqual := func(a, b []string) bool {
 		if len(a) != len(b) {
@@ -468,7 +650,7 @@ func TestIgnores(t *testing.T) {
 	ioutil.WriteFile("testdata/.stfolder", nil, 0644)
 	ioutil.WriteFile("testdata/.stignore", []byte(".*\nquux\n"), 0644)
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.StartFolderRO("default")
@@ -543,7 +725,7 @@ func TestIgnores(t *testing.T) {
 }
 
 func TestRefuseUnknownBits(t *testing.T) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.ServeBackground()
@@ -562,13 +744,17 @@ func TestRefuseUnknownBits(t *testing.T) {
 			Name:  "invalid3",
 			Flags: (1 << 31) &^ protocol.FlagInvalid,
 		},
+		{
+			Name:  "invalid4",
+			Flags: protocol.FlagHashAlgorithmMask,
+		},
 		{
 			Name:  "valid",
-			Flags: protocol.FlagsAll &^ (protocol.FlagInvalid | protocol.FlagSymlink),
+			Flags: protocol.FlagsAll&^(protocol.FlagInvalid|protocol.FlagSymlink|protocol.FlagHashAlgorithmMask|protocol.FlagInvalidReasonMask) | protocol.BLAKE2b.Flags(),
 		},
 	}, 0, nil)
 
-	for _, name := range []string{"invalid1", "invalid2", "invalid3"} {
+	for _, name := range []string{"invalid1", "invalid2", "invalid3", "invalid4"} {
 		f, ok := m.CurrentGlobalFile("default", name)
 		if ok || f.Name == name {
 			t.Error("Invalid file found or name match")
@@ -581,7 +767,7 @@ func TestRefuseUnknownBits(t *testing.T) {
 }
 
 func TestROScanRecovery(t *testing.T) {
-	ldb, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	ldb := db.OpenMemory()
 	set := db.NewFileSet("default", ldb)
 	set.Update(protocol.LocalDeviceID, []protocol.FileInfo{
 		{Name: "dummyfile"},
@@ -665,7 +851,7 @@ func TestROScanRecovery(t *testing.T) {
 }
 
 func TestRWScanRecovery(t *testing.T) {
-	ldb, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	ldb := db.OpenMemory()
 	set := db.NewFileSet("default", ldb)
 	set.Update(protocol.LocalDeviceID, []protocol.FileInfo{
 		{Name: "dummyfile"},
@@ -749,7 +935,7 @@ func TestRWScanRecovery(t *testing.T) {
 }
 
 func TestGlobalDirectoryTree(t *testing.T) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.ServeBackground()
@@ -999,7 +1185,7 @@ func TestGlobalDirectoryTree(t *testing.T) {
 }
 
 func TestGlobalDirectorySelfFixing(t *testing.T) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.ServeBackground()
@@ -1173,7 +1359,7 @@ func BenchmarkTree_100_10(b *testing.B) {
 }
 
 func benchmarkTree(b *testing.B, n1, n2 int) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	m.ServeBackground()
@@ -1191,7 +1377,7 @@ func benchmarkTree(b *testing.B, n1, n2 int) {
 }
 
 func TestIgnoreDelete(t *testing.T) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 
 	// This folder should ignore external deletes
@@ -1226,3 +1412,239 @@ func TestIgnoreDelete(t *testing.T) {
 		t.Fatal("foo should not be marked for deletion")
 	}
 }
+
+func TestScanWaiting(t *testing.T) {
+	cfg := defaultConfig.Raw()
+	cfg.Options.MaxConcurrentScans = 1
+	wrapper := config.Wrap("/tmp/test", cfg)
+
+	db := db.OpenMemory()
+	m := NewModel(wrapper, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(defaultFolderConfig)
+	m.StartFolderRO("default")
+	m.ServeBackground()
+	defer m.Stop()
+	m.ScanFolder("default")
+
+	// Occupy the only scan slot, as if another folder was scanning.
+	m.scanSlots <- struct{}{}
+
+	done := make(chan error)
+	go func() {
+		done <- m.ScanFolder("default")
+	}()
+
+	var state string
+	for i := 0; i < 100; i++ {
+		state, _, _ = m.State("default")
+		if state == "scan-waiting" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if state != "scan-waiting" {
+		t.Fatalf("Incorrect state %q != scan-waiting", state)
+	}
+
+	<-m.scanSlots
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Scan did not complete after the slot was released")
+	}
+	if state, _, _ := m.State("default"); state != "idle" {
+		t.Errorf("Incorrect state %q != idle", state)
+	}
+}
+
+func TestScanWaitingStop(t *testing.T) {
+	cfg := defaultConfig.Raw()
+	cfg.Options.MaxConcurrentScans = 1
+	wrapper := config.Wrap("/tmp/test", cfg)
+
+	db := db.OpenMemory()
+	m := NewModel(wrapper, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(defaultFolderConfig)
+	m.StartFolderRO("default")
+	m.ServeBackground()
+	m.ScanFolder("default")
+
+	// Occupy the only scan slot for good.
+	m.scanSlots <- struct{}{}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.ScanFolder("default")
+	}()
+
+	for i := 0; i < 100; i++ {
+		if state, _, _ := m.State("default"); state == "scan-waiting" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case err := <-done:
+		if err != errFolderStopped {
+			t.Errorf("Incorrect error %v != %v", err, errFolderStopped)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Waiting scan did not return when the folder was stopped")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Model did not stop while a scan was waiting")
+	}
+}
+
+func TestFolderHashLimit(t *testing.T) {
+	fcfg := defaultFolderConfig
+	fcfg.MaxHashMBps = 10
+	from := defaultConfig.Raw()
+	from.Folders = []config.FolderConfiguration{fcfg}
+
+	db := db.OpenMemory()
+	m := NewModel(config.Wrap("/tmp/test", from), protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(fcfg)
+
+	bucket := m.folderHashLimits["default"]
+	if bucket == nil || bucket.Rate() != 10e6 {
+		t.Fatalf("Incorrect folder hash limit %v", bucket)
+	}
+
+	to := from
+	to.Folders = []config.FolderConfiguration{fcfg}
+	to.Folders[0].MaxHashMBps = 20
+	if !m.CommitConfiguration(from, to) {
+		t.Fatal("Changing the folder hash limit should not require a restart")
+	}
+	if bucket := m.folderHashLimits["default"]; bucket == nil || bucket.Rate() != 20e6 {
+		t.Errorf("Incorrect folder hash limit %v after change", bucket)
+	}
+
+	from = to
+	to.Folders = []config.FolderConfiguration{fcfg}
+	to.Folders[0].MaxHashMBps = 0
+	if !m.CommitConfiguration(from, to) {
+		t.Fatal("Removing the folder hash limit should not require a restart")
+	}
+	if bucket, ok := m.folderHashLimits["default"]; ok {
+		t.Errorf("Unexpected folder hash limit %v after removal", bucket)
+	}
+}
+
+func TestInvalidFiles(t *testing.T) {
+	db := db.OpenMemory()
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(defaultFolderConfig)
+
+	var version protocol.Vector
+	version = version.Update(1)
+	file := func(name string, reason protocol.InvalidReason) protocol.FileInfo {
+		return protocol.FileInfo{
+			Name:    name,
+			Flags:   protocol.FlagInvalid | reason.Flags(),
+			Version: version,
+		}
+	}
+
+	m.updateLocals("default", []protocol.FileInfo{file("large", protocol.InvalidReasonTooLarge)})
+	if files := m.InvalidFiles("default"); len(files) != 1 || files[0].Path != "large" {
+		t.Errorf("Incorrect invalid files %v", files)
+	}
+
+	// The cached list is rebuilt once the local index changes.
+	m.updateLocals("default", []protocol.FileInfo{file("recent", protocol.InvalidReasonTooRecent)})
+	if files := m.InvalidFiles("default"); len(files) != 2 {
+		t.Errorf("Incorrect invalid files %v after update", files)
+	}
+}
+
+func TestPullKeepsInvalidLocalFile(t *testing.T) {
+	cases := []struct {
+		reason protocol.InvalidReason
+		edit   []byte
+	}{
+		{protocol.InvalidReasonTooRecent, []byte("local edit")},
+		{protocol.InvalidReasonTooLarge, bytes.Repeat([]byte("local edit"), 1<<18)},
+	}
+	for _, tc := range cases {
+		testPullKeepsInvalidLocalFile(t, tc.reason, tc.edit)
+	}
+}
+
+func testPullKeepsInvalidLocalFile(t *testing.T, reason protocol.InvalidReason, edit []byte) {
+	dir, err := ioutil.TempDir("", "syncthing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "file")
+	old := time.Now().Add(-2 * time.Hour)
+	if err := ioutil.WriteFile(name, []byte("synced"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(name, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	fcfg := defaultFolderConfig
+	fcfg.RawPath = dir
+	if reason == protocol.InvalidReasonTooRecent {
+		fcfg.IgnoreModifiedWithinS = 3600
+	} else {
+		fcfg.IgnoreLargerThanMiB = 1
+	}
+	cfg := defaultConfig.Raw()
+	cfg.Folders = []config.FolderConfiguration{fcfg}
+	wrapper := config.Wrap("/tmp/test", cfg)
+
+	m := NewModel(wrapper, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+	m.StartFolderRO("default")
+	m.ServeBackground()
+	m.ScanFolder("default")
+
+	// The other device has the file as it was synced.
+	f, ok := m.CurrentFolderFile("default", "file")
+	if !ok || f.IsInvalid() {
+		t.Fatalf("file should be in the index and valid, %v", f)
+	}
+	m.Index(device1, "default", []protocol.FileInfo{f}, 0, nil)
+
+	// A local edit makes the file invalid.
+	if err := ioutil.WriteFile(name, edit, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if reason == protocol.InvalidReasonTooLarge {
+		if err := os.Chtimes(name, old, old.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.ScanFolder("default")
+	f, _ = m.CurrentFolderFile("default", "file")
+	if f.InvalidReason() != reason {
+		t.Fatalf("file should be invalid as %v, %v", reason, f)
+	}
+
+	p := newRWFolder(m, 0, fcfg)
+	p.clearErrors()
+	if changed := p.pullerIteration(nil); changed != 0 {
+		t.Errorf("%v: %d files pulled, expected none", reason, changed)
+	}
+	if bs, err := ioutil.ReadFile(name); err != nil || !bytes.Equal(bs, edit) {
+		t.Errorf("%v: local file changed (%v)", reason, err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+var errNotShared = errors.New("folder is not shared with the device")
+
+// How the version a device has of a file relates to that of another.
+const (
+	DiffNewer    = "newer"    // both have the file, the first a newer version
+	DiffOlder    = "older"    // both have the file, the first an older version
+	DiffConflict = "conflict" // both have the file, in concurrent versions
+	DiffOnlyA    = "onlyA"    // only the first device has the file
+	DiffOnlyB    = "onlyB"    // only the second device has the file
+)
+
+// A FileDiff is a file on which the views of a folder of two devices differ.
+// A or B is nil when that device does not have the file.
+type FileDiff struct {
+	Name     string
+	A, B     *db.FileInfoTruncated
+	Relation string
+}
+
+// folderDevice returns the folder set, and the ID the device has in it,
+// verifying that the folder is shared with the device.
+func (m *Model) folderDevice(folder string, device protocol.DeviceID) (*db.FileSet, protocol.DeviceID, error) {
+	m.fmut.RLock()
+	defer m.fmut.RUnlock()
+
+	fs, ok := m.folderFiles[folder]
+	if !ok {
+		return nil, device, errors.New("no such folder")
+	}
+	if device == m.id || device == protocol.LocalDeviceID {
+		return fs, protocol.LocalDeviceID, nil
+	}
+	for _, dev := range m.folderDevices[folder] {
+		if dev == device {
+			return fs, device, nil
+		}
+	}
+	return nil, device, errNotShared
+}
+
+// RemoteNeedFolderFiles returns the given page of the files in the global
+// index of the folder that the device lacks, or has an older version of,
+// according to the index it has announced, along with the total number of
+// them. Only the subtrees the device subscribes to are considered.
+func (m *Model) RemoteNeedFolderFiles(device protocol.DeviceID, folder string, page, perpage int) ([]db.FileInfoTruncated, int, error) {
+	fs, dbDevice, err := m.folderDevice(folder, device)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m.fmut.RLock()
+	subs := m.remoteSubs[folder][device]
+	m.fmut.RUnlock()
+
+	total := 0
+	files := make([]db.FileInfoTruncated, 0, 32)
+
+	fs.WithNeedTruncated(dbDevice, func(fi db.FileIntf) bool {
+		f := fi.(db.FileInfoTruncated)
+		if !subs.covers(f.Name) {
+			return true
+		}
+		// Compare page numbers rather than offsets, which could overflow.
+		if total/perpage == page-1 {
+			files = append(files, f)
+		}
+		total++
+		return true
+	})
+
+	return files, total, nil
+}
+
+// FolderDiff returns the given page of the files on which the announced
+// indexes of the folder of the two devices differ, sorted by name, along
+// with the total number of differences.
+func (m *Model) FolderDiff(folder string, a, b protocol.DeviceID, page, perpage int) ([]FileDiff, int, error) {
+	fs, dbA, err := m.folderDevice(folder, a)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, dbB, err := m.folderDevice(folder, b)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	haveA := make(map[string]db.FileInfoTruncated)
+	fs.WithHaveTruncated(dbA, func(fi db.FileIntf) bool {
+		f := fi.(db.FileInfoTruncated)
+		haveA[f.Name] = f
+		return true
+	})
+
+	var diffs []FileDiff
+	fs.WithHaveTruncated(dbB, func(fi db.FileIntf) bool {
+		fb := fi.(db.FileInfoTruncated)
+		fa, ok := haveA[fb.Name]
+		if !ok {
+			diffs = append(diffs, FileDiff{Name: fb.Name, B: &fb, Relation: DiffOnlyB})
+			return true
+		}
+		delete(haveA, fb.Name)
+
+		var relation string
+		switch fa.Version.Compare(fb.Version) {
+		case protocol.Equal:
+			return true
+		case protocol.Greater:
+			relation = DiffNewer
+		case protocol.Lesser:
+			relation = DiffOlder
+		default:
+			relation = DiffConflict
+		}
+		diffs = append(diffs, FileDiff{Name: fb.Name, A: &fa, B: &fb, Relation: relation})
+		return true
+	})
+	for name := range haveA {
+		fa := haveA[name]
+		diffs = append(diffs, FileDiff{Name: name, A: &fa, Relation: DiffOnlyA})
+	}
+
+	sort.Sort(fileDiffsByName(diffs))
+
+	// The bounds are compared before multiplying or adding so that huge
+	// page numbers or sizes can't overflow.
+	total := len(diffs)
+	start := total
+	if page-1 <= total/perpage {
+		start = (page - 1) * perpage
+	}
+	end := total
+	if total-start > perpage {
+		end = start + perpage
+	}
+	return diffs[start:end], total, nil
+}
+
+type fileDiffsByName []FileDiff
+
+func (s fileDiffsByName) Len() int           { return len(s) }
+func (s fileDiffsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s fileDiffsByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
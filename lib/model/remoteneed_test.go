@@ -0,0 +1,123 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func setupDiffModel() *Model {
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(defaultFolderConfig)
+
+	v1 := protocol.Vector{{ID: 1, Value: 1}}
+	v2 := protocol.Vector{{ID: 1, Value: 2}}
+	m.folderFiles["default"].Update(protocol.LocalDeviceID, []protocol.FileInfo{
+		{Name: "a", Version: v2},
+		{Name: "b", Version: v1},
+		{Name: "c", Version: v1},
+	})
+	m.Index(device1, "default", []protocol.FileInfo{
+		{Name: "a", Version: v1},
+		{Name: "b", Version: v1},
+		{Name: "d", Version: protocol.Vector{{ID: 2, Value: 1}}},
+	}, 0, nil)
+
+	return m
+}
+
+func TestRemoteNeedFolderFiles(t *testing.T) {
+	m := setupDiffModel()
+
+	files, total, err := m.RemoteNeedFolderFiles(device1, "default", 1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	if total != 2 || !reflect.DeepEqual(names, []string{"a", "c"}) {
+		t.Errorf("unexpected need %v (total %d)", names, total)
+	}
+
+	files, total, _ = m.RemoteNeedFolderFiles(device1, "default", 2, 1)
+	if total != 2 || len(files) != 1 || files[0].Name != "c" {
+		t.Errorf("unexpected second page %v (total %d)", files, total)
+	}
+
+	files, total, _ = m.RemoteNeedFolderFiles(device1, "default", 3, int(^uint(0)>>1))
+	if total != 2 || len(files) != 0 {
+		t.Errorf("unexpected page past the end %v (total %d)", files, total)
+	}
+
+	if _, _, err := m.RemoteNeedFolderFiles(device2, "default", 1, 100); err != errNotShared {
+		t.Errorf("unexpected error %v for a device not sharing the folder", err)
+	}
+	if _, _, err := m.RemoteNeedFolderFiles(device1, "nonexistent", 1, 100); err == nil {
+		t.Error("unexpected nil error for a nonexistent folder")
+	}
+}
+
+func TestFolderDiff(t *testing.T) {
+	m := setupDiffModel()
+	m.folderFiles["default"].Update(device1, []protocol.FileInfo{
+		{Name: "conflict", Version: protocol.Vector{{ID: 2, Value: 1}}},
+	})
+	m.folderFiles["default"].Update(protocol.LocalDeviceID, []protocol.FileInfo{
+		{Name: "conflict", Version: protocol.Vector{{ID: 1, Value: 1}}},
+	})
+
+	diffs, total, err := m.FolderDiff("default", protocol.LocalDeviceID, device1, 1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res [][2]string
+	for _, d := range diffs {
+		res = append(res, [2]string{d.Name, d.Relation})
+		if (d.A == nil) != (d.Relation == DiffOnlyB) || (d.B == nil) != (d.Relation == DiffOnlyA) {
+			t.Errorf("%s: unexpected sides %v, %v for %s", d.Name, d.A, d.B, d.Relation)
+		}
+	}
+	expected := [][2]string{
+		{"a", DiffNewer},
+		{"c", DiffOnlyA},
+		{"conflict", DiffConflict},
+		{"d", DiffOnlyB},
+	}
+	if total != 4 || !reflect.DeepEqual(res, expected) {
+		t.Errorf("unexpected diff %v (total %d)", res, total)
+	}
+
+	diffs, _, _ = m.FolderDiff("default", device1, protocol.LocalDeviceID, 1, 1)
+	if len(diffs) != 1 || diffs[0].Relation != DiffOlder {
+		t.Errorf("unexpected reverse diff %v", diffs)
+	}
+
+	diffs, total, _ = m.FolderDiff("default", device1, protocol.LocalDeviceID, 5, 1)
+	if total != 4 || len(diffs) != 0 {
+		t.Errorf("unexpected page past the end %v (total %d)", diffs, total)
+	}
+
+	maxInt := int(^uint(0) >> 1)
+	diffs, total, _ = m.FolderDiff("default", device1, protocol.LocalDeviceID, 3, maxInt)
+	if total != 4 || len(diffs) != 0 {
+		t.Errorf("unexpected page past the end %v (total %d)", diffs, total)
+	}
+	diffs, total, _ = m.FolderDiff("default", device1, protocol.LocalDeviceID, maxInt, 2)
+	if total != 4 || len(diffs) != 0 {
+		t.Errorf("unexpected page past the end %v (total %d)", diffs, total)
+	}
+	diffs, total, _ = m.FolderDiff("default", device1, protocol.LocalDeviceID, 1, maxInt)
+	if total != 4 || len(diffs) != 4 {
+		t.Errorf("unexpected single page %v (total %d)", diffs, total)
+	}
+}
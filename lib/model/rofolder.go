@@ -137,6 +137,10 @@ func (s *roFolder) Stop() {
 	close(s.stop)
 }
 
+func (s *roFolder) stopped() <-chan struct{} {
+	return s.stop
+}
+
 func (s *roFolder) IndexUpdated() {
 }
 
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/syncthing/syncthing/lib/config"
@@ -92,6 +93,8 @@ type rwFolder struct {
 	pullers     int
 	shortID     uint64
 	order       config.PullOrder
+	caseFold    bool              // The file system ignores case in file names
+	caseNames   *osutil.CaseCache // Names on disk, cached for the length of a pull
 
 	stop        chan struct{}
 	queue       *jobQueue
@@ -125,6 +128,7 @@ func newRWFolder(m *Model, shortID uint64, cfg config.FolderConfiguration) *rwFo
 		pullers:     cfg.Pullers,
 		shortID:     shortID,
 		order:       cfg.Order,
+		caseFold:    cfg.CaseInsensitiveFS,
 
 		stop:        make(chan struct{}),
 		queue:       newJobQueue(),
@@ -371,6 +375,10 @@ func (p *rwFolder) Stop() {
 	close(p.stop)
 }
 
+func (p *rwFolder) stopped() <-chan struct{} {
+	return p.stop
+}
+
 func (p *rwFolder) IndexUpdated() {
 	select {
 	case p.remoteIndex <- struct{}{}:
@@ -400,6 +408,11 @@ func (p *rwFolder) String() string {
 // might have failed). One puller iteration handles all files currently
 // flagged as needed in the folder.
 func (p *rwFolder) pullerIteration(ignores *ignore.Matcher) int {
+	if p.caseFold {
+		p.caseNames = osutil.NewCaseCache(p.dir)
+		defer func() { p.caseNames = nil }()
+	}
+
 	pullChan := make(chan pullBlockState)
 	copyChan := make(chan copyBlocksState)
 	finisherChan := make(chan *sharedPullerState)
@@ -448,6 +461,7 @@ func (p *rwFolder) pullerIteration(ignores *ignore.Matcher) int {
 
 	p.model.fmut.RLock()
 	folderFiles := p.model.folderFiles[p.folder]
+	subs := newSubscriptions(p.model.folderCfgs[p.folder].Subscriptions)
 	p.model.fmut.RUnlock()
 
 	// !!!
@@ -469,11 +483,26 @@ func (p *rwFolder) pullerIteration(ignores *ignore.Matcher) int {
 
 		file := intf.(protocol.FileInfo)
 
-		if ignores.Match(file.Name) {
+		if ignores.MatchFile(file.Name, file.IsDirectory()) {
 			// This is an ignored file. Skip it, continue iteration.
 			return true
 		}
 
+		if !subs.covers(file.Name) {
+			// Outside of the subtrees we subscribe to.
+			return true
+		}
+
+		if cur, ok := p.model.CurrentFolderFile(p.folder, file.Name); ok && cur.IsInvalid() && cur.InvalidReason() != protocol.InvalidReasonNone {
+			// We have a local file we're not syncing, such as one that is
+			// too large or still being written to. It's not in the global
+			// list, which is why it appears needed; leave it alone.
+			if debug {
+				l.Debugln(p, "not pulling", file.Name, "over local file:", cur.InvalidReason())
+			}
+			return true
+		}
+
 		if debug {
 			l.Debugln(p, "handling", file.Name)
 		}
@@ -498,6 +527,12 @@ func (p *rwFolder) pullerIteration(ignores *ignore.Matcher) int {
 			}
 		case file.IsDirectory() && !file.IsSymlink():
 			// A new or changed directory
+			if err := p.checkCaseConflict(file.Name); err != nil {
+				l.Infof("Puller (folder %q, dir %q): %v", p.folder, file.Name, err)
+				p.newError(file.Name, err)
+				break
+			}
+			p.claimCaseName(file.Name)
 			if debug {
 				l.Debugln("Creating directory", file.Name)
 			}
@@ -545,6 +580,45 @@ nextFile:
 			continue
 		}
 
+		if err := p.checkCaseConflict(f.Name); err != nil {
+			cerr, ok := err.(caseConflictError)
+			desired, pending := fileDeletions[cerr.existing]
+			if !ok || !pending {
+				l.Infof("Puller (folder %q, file %q): %v", p.folder, f.Name, err)
+				p.newError(f.Name, err)
+				p.queue.Done(fileName)
+				continue
+			}
+
+			// Only the case of the name is changing. The old file must not
+			// be used as a rename source for anything else, as its name now
+			// refers to the new file.
+			delete(fileDeletions, desired.Name)
+			df, ok := p.model.CurrentFolderFile(p.folder, desired.Name)
+			if ok && len(df.Blocks) > 0 {
+				key := string(df.Blocks[0].Hash)
+				for i, candidate := range buckets[key] {
+					if candidate.Name == df.Name {
+						lidx := len(buckets[key]) - 1
+						buckets[key][i] = buckets[key][lidx]
+						buckets[key] = buckets[key][:lidx]
+						break
+					}
+				}
+			}
+
+			if ok && !f.IsSymlink() && !df.IsSymlink() && scanner.BlocksEqual(df.Blocks, f.Blocks) {
+				p.renameFile(desired, f)
+				p.queue.Done(fileName)
+				continue
+			}
+
+			// The contents differ, so get the old file out of the way
+			// before writing the new one.
+			p.deleteFile(desired)
+		}
+		p.claimCaseName(f.Name)
+
 		// Local file can be already deleted, but with a lower version
 		// number, hence the deletion coming in again as part of
 		// WithNeed, furthermore, the file can simply be of the wrong type if
@@ -775,6 +849,13 @@ func (p *rwFolder) deleteFile(file protocol.FileInfo) {
 
 	realName := filepath.Join(p.dir, file.Name)
 
+	if p.checkCaseConflict(file.Name) != nil {
+		// What is on disk is a different file, differing only in the case
+		// of the name. It's not ours to delete.
+		p.dbUpdates <- dbUpdateJob{file, dbUpdateDeleteFile}
+		return
+	}
+
 	cur, ok := p.model.CurrentFolderFile(p.folder, file.Name)
 	if ok && p.inConflict(cur.Version, file.Version) {
 		// There is a conflict here. Move the file to a conflict copy instead
@@ -803,6 +884,46 @@ func (p *rwFolder) deleteFile(file protocol.FileInfo) {
 	}
 }
 
+// A caseConflictError is returned by checkCaseConflict when a name exists on
+// disk with a case different from the one wanted.
+type caseConflictError struct {
+	existing string
+}
+
+func (e caseConflictError) Error() string {
+	return fmt.Sprintf("name conflicts with existing %q on case insensitive file system", e.existing)
+}
+
+// checkCaseConflict returns a caseConflictError if the folder is on a case
+// insensitive file system, and something other than the given name, but
+// equal to it apart from case, exists on disk.
+func (p *rwFolder) checkCaseConflict(name string) error {
+	if !p.caseFold {
+		return nil
+	}
+	if cur, ok := p.model.CurrentFolderFile(p.folder, name); ok && !cur.IsDeleted() {
+		// We have the file under this exact name already.
+		return nil
+	}
+	caseNames := p.caseNames
+	if caseNames == nil {
+		// Not during a pull; read the directories afresh.
+		caseNames = osutil.NewCaseCache(p.dir)
+	}
+	if existing, ok := caseNames.Conflict(name); ok {
+		return caseConflictError{existing}
+	}
+	return nil
+}
+
+// claimCaseName records that name is about to be created by the current pull,
+// so that other needed names equal to it apart from case conflict with it.
+func (p *rwFolder) claimCaseName(name string) {
+	if p.caseNames != nil {
+		p.caseNames.Add(name)
+	}
+}
+
 // renameFile attempts to rename an existing file to a destination
 // and set the right attributes on it.
 func (p *rwFolder) renameFile(source, target protocol.FileInfo) {
@@ -843,12 +964,14 @@ func (p *rwFolder) renameFile(source, target protocol.FileInfo) {
 	from := filepath.Join(p.dir, source.Name)
 	to := filepath.Join(p.dir, target.Name)
 
-	if p.versioner != nil {
+	if p.versioner != nil && !strings.EqualFold(source.Name, target.Name) {
 		err = osutil.Copy(from, to)
 		if err == nil {
 			err = osutil.InWritableDir(p.versioner.Archive, from)
 		}
 	} else {
+		// Also taken when only the case of the name changes, as copying
+		// would truncate the source on a case insensitive file system.
 		err = osutil.TryRename(from, to)
 	}
 
@@ -988,7 +1111,7 @@ func (p *rwFolder) handleFile(file protocol.FileInfo, copyChan chan<- copyBlocks
 
 	// Check for an old temporary file which might have some blocks we could
 	// reuse.
-	tempBlocks, err := scanner.HashFile(tempName, protocol.BlockSize, 0, nil)
+	tempBlocks, err := scanner.HashFile(tempName, protocol.BlockSize, file.HashAlgorithm(), 0, nil)
 	if err == nil {
 		// Check for any reusable blocks in the temp file
 		tempCopyBlocks, _ := scanner.BlockDiff(tempBlocks, file.Blocks)
@@ -1096,6 +1219,7 @@ func (p *rwFolder) shortcutSymlink(file protocol.FileInfo) (err error) {
 // the relevant copies when possible, or passes it to the puller routine.
 func (p *rwFolder) copierRoutine(in <-chan copyBlocksState, pullChan chan<- pullBlockState, out chan<- *sharedPullerState) {
 	buf := make([]byte, protocol.BlockSize)
+	zeroBlock := make([]byte, protocol.BlockSize)
 
 	for state := range in {
 		dstFd, err := state.tempFile()
@@ -1119,6 +1243,20 @@ func (p *rwFolder) copierRoutine(in <-chan copyBlocksState, pullChan chan<- pull
 		p.model.fmut.RUnlock()
 
 		for _, block := range state.blocks {
+			if scanner.IsZeroBlock(block, state.file.HashAlgorithm()) {
+				// The temp file is already of the right size, so the block
+				// reads as zeros unless we're reusing an old temp file
+				// with something else in that spot.
+				if state.reused > 0 {
+					if _, err := dstFd.WriteAt(zeroBlock, block.Offset); err != nil {
+						state.fail("dst write", err)
+						break
+					}
+				}
+				state.copyDone()
+				continue
+			}
+
 			buf = buf[:int(block.Size)]
 			found := p.model.finder.Iterate(folders, block.Hash, func(folder, file string, index int32) bool {
 				fd, err := os.Open(filepath.Join(folderRoots[folder], file))
@@ -1132,9 +1270,13 @@ func (p *rwFolder) copierRoutine(in <-chan copyBlocksState, pullChan chan<- pull
 					return false
 				}
 
-				hash, err := scanner.VerifyBuffer(buf, block)
+				hash, err := scanner.VerifyBuffer(buf, block, state.file.HashAlgorithm())
 				if err != nil {
-					if hash != nil {
+					// The block map entry can only be corrected if the
+					// source file was hashed with the same algorithm as the
+					// file we are pulling; otherwise the computed hash is
+					// meaningless for it.
+					if cf, ok := p.model.CurrentFolderFile(folder, file); hash != nil && ok && cf.HashAlgorithm() == state.file.HashAlgorithm() {
 						if debug {
 							l.Debugf("Finder block mismatch in %s:%s:%d expected %q got %q", folder, file, index, block.Hash, hash)
 						}
@@ -1225,7 +1367,7 @@ func (p *rwFolder) pullerRoutine(in <-chan pullBlockState, out chan<- *sharedPul
 
 			// Verify that the received block matches the desired hash, if not
 			// try pulling it from another device.
-			_, lastError = scanner.VerifyBuffer(buf, state.block)
+			_, lastError = scanner.VerifyBuffer(buf, state.block, state.file.HashAlgorithm())
 			if lastError != nil {
 				if debug {
 					l.Debugln("request:", p.folder, state.file.Name, state.block.Offset, state.block.Size, "hash mismatch")
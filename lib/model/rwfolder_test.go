@@ -7,17 +7,16 @@
 package model
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/syncthing/syncthing/lib/db"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/scanner"
 	"github.com/syncthing/syncthing/lib/sync"
-
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/storage"
 )
 
 func init() {
@@ -69,7 +68,7 @@ func TestHandleFile(t *testing.T) {
 	requiredFile := existingFile
 	requiredFile.Blocks = blocks[1:]
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	// Update index
@@ -125,7 +124,7 @@ func TestHandleFileWithTemp(t *testing.T) {
 	requiredFile := existingFile
 	requiredFile.Blocks = blocks[1:]
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	// Update index
@@ -187,7 +186,7 @@ func TestCopierFinder(t *testing.T) {
 	requiredFile.Blocks = blocks[1:]
 	requiredFile.Name = "file2"
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 	// Update index
@@ -243,7 +242,7 @@ func TestCopierFinder(t *testing.T) {
 	}
 
 	// Verify that the fetched blocks have actually been written to the temp file
-	blks, err := scanner.HashFile(tempFile, protocol.BlockSize, 0, nil)
+	blks, err := scanner.HashFile(tempFile, protocol.BlockSize, protocol.SHA256, 0, nil)
 	if err != nil {
 		t.Log(err)
 	}
@@ -258,13 +257,128 @@ func TestCopierFinder(t *testing.T) {
 	os.Remove(tempFile)
 }
 
+// Test that blocks of all zeros are neither pulled nor written, leaving a
+// temp file of the right size and contents.
+func TestCopierZeroBlocks(t *testing.T) {
+	zero := protocol.BlockInfo{Size: protocol.BlockSize, Hash: blocks[0].Hash}
+	requiredFile := protocol.FileInfo{
+		Name: "sparse",
+		Blocks: []protocol.BlockInfo{
+			zero, zero, zero,
+		},
+	}
+	scanner.PopulateOffsets(requiredFile.Blocks)
+
+	tempFile := filepath.Join("testdata", defTempNamer.TempName("sparse"))
+	os.Remove(tempFile)
+	defer os.Remove(tempFile)
+
+	db := db.OpenMemory()
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
+	m.AddFolder(defaultFolderConfig)
+
+	p := rwFolder{
+		folder:    "default",
+		dir:       "testdata",
+		model:     m,
+		errors:    make(map[string]string),
+		errorsMut: sync.NewMutex(),
+	}
+
+	copyChan := make(chan copyBlocksState)
+	pullChan := make(chan pullBlockState, 3)
+	finisherChan := make(chan *sharedPullerState, 1)
+
+	go p.copierRoutine(copyChan, pullChan, finisherChan)
+
+	p.handleFile(requiredFile, copyChan, finisherChan)
+
+	finish := <-finisherChan
+	select {
+	case <-pullChan:
+		t.Fatal("Zero block was pulled")
+	default:
+	}
+	if err := finish.failed(); err != nil {
+		t.Fatal(err)
+	}
+	if finish.copyNeeded != 0 {
+		t.Errorf("copyNeeded %d != 0", finish.copyNeeded)
+	}
+	finish.finalClose()
+
+	data, err := ioutil.ReadFile(tempFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3*protocol.BlockSize {
+		t.Fatalf("temp file size %d != %d", len(data), 3*protocol.BlockSize)
+	}
+	for _, b := range data {
+		if b != 0 {
+			t.Fatal("temp file contains non-zero data")
+		}
+	}
+}
+
+func TestCaseConflict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "casefold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A file we don't have in the index, as created by the user on a case
+	// insensitive file system.
+	path := filepath.Join(dir, "Report.docx")
+	if err := ioutil.WriteFile(path, []byte("local data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(defaultFolderConfig)
+
+	p := rwFolder{
+		folder:    "default",
+		dir:       dir,
+		model:     m,
+		caseFold:  true,
+		dbUpdates: make(chan dbUpdateJob, 1),
+		errors:    make(map[string]string),
+		errorsMut: sync.NewMutex(),
+	}
+
+	err = p.checkCaseConflict("report.docx")
+	if cerr, ok := err.(caseConflictError); !ok || cerr.existing != "Report.docx" {
+		t.Fatalf("expected case conflict with Report.docx, got %v", err)
+	}
+	if err := p.checkCaseConflict("Report.docx"); err != nil {
+		t.Errorf("unexpected conflict for exact name: %v", err)
+	}
+
+	// Deleting the other name must leave the local file alone.
+	p.deleteFile(protocol.FileInfo{Name: "report.docx", Flags: protocol.FlagDeleted})
+	if _, err := os.Stat(path); err != nil {
+		t.Error("local file was removed:", err)
+	}
+	if job := <-p.dbUpdates; job.file.Name != "report.docx" || job.jobType != dbUpdateDeleteFile {
+		t.Errorf("unexpected db update %+v", job)
+	}
+
+	// Without the setting, there is no conflict checking.
+	p.caseFold = false
+	if err := p.checkCaseConflict("report.docx"); err != nil {
+		t.Errorf("unexpected conflict with case folding disabled: %v", err)
+	}
+}
+
 // Test that updating a file removes it's old blocks from the blockmap
 func TestCopierCleanup(t *testing.T) {
 	iterFn := func(folder, file string, index int32) bool {
 		return true
 	}
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 
@@ -313,7 +427,7 @@ func TestCopierCleanup(t *testing.T) {
 // Make sure that the copier routine hashes the content when asked, and pulls
 // if it fails to find the block.
 func TestLastResortPulling(t *testing.T) {
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 
@@ -387,7 +501,7 @@ func TestDeregisterOnFailInCopy(t *testing.T) {
 	}
 	defer os.Remove("testdata/" + defTempNamer.TempName("filex"))
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
@@ -477,7 +591,7 @@ func TestDeregisterOnFailInPull(t *testing.T) {
 	}
 	defer os.Remove("testdata/" + defTempNamer.TempName("filex"))
 
-	db, _ := leveldb.Open(storage.NewMemStorage(), nil)
+	db := db.OpenMemory()
 	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db)
 	m.AddFolder(defaultFolderConfig)
 
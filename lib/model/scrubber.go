@@ -0,0 +1,462 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/ratelimit"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+const (
+	// Wait at least this long after startup before the first scrub, so we
+	// don't compete with the initial scan and index exchange.
+	scrubStartupDelay = 10 * time.Minute
+	// How often to check whether the folder has become idle while we are
+	// waiting to continue scrubbing.
+	scrubIdleCheckInterval = 2 * time.Second
+	// Keep at most this many mismatches in the status.
+	maxScrubMismatches = 1000
+)
+
+var (
+	errScrubDisabled = errors.New("scrubbing is not enabled for this folder")
+	errFileChanged   = errors.New("file changed during scrub")
+	errScrubStopped  = errors.New("scrub stopped")
+	errScrubRunning  = errors.New("a scrub is already running")
+	errNoRepairPeer  = errors.New("no connected device has this version of the file")
+)
+
+// ScrubStatus describes the progress and result of the latest scrub of a
+// folder.
+type ScrubStatus struct {
+	Running    bool            `json:"running"`
+	Started    time.Time       `json:"started"`
+	Finished   time.Time       `json:"finished"`
+	Files      int             `json:"files"`
+	Bytes      int64           `json:"bytes"`
+	Mismatches []ScrubMismatch `json:"mismatches"`
+}
+
+// ScrubMismatch describes a file whose contents on disk do not match the
+// block hashes recorded in the index.
+type ScrubMismatch struct {
+	File     string    `json:"file"`
+	Blocks   []int     `json:"blocks"`
+	Repaired bool      `json:"repaired"`
+	Error    string    `json:"error,omitempty"`
+	Detected time.Time `json:"detected"`
+}
+
+// The folderScrubber periodically rereads all files in a folder and compares
+// their contents against the stored block hashes, to detect corruption that
+// is invisible to the scanner as it doesn't affect size, modification time
+// or permissions.
+type folderScrubber struct {
+	folder      string
+	dir         string
+	ignorePerms bool
+	intv        time.Duration
+	rate        int // KiB/s
+	repair      bool
+	model       *Model
+	timer       *time.Timer
+	stop        chan struct{}
+	scrubNow    chan chan error
+	scrubSoon   chan struct{}
+
+	mut    sync.Mutex
+	status ScrubStatus
+}
+
+func newFolderScrubber(model *Model, cfg config.FolderConfiguration) *folderScrubber {
+	return &folderScrubber{
+		folder:      cfg.ID,
+		dir:         cfg.Path(),
+		ignorePerms: cfg.IgnorePerms,
+		intv:        time.Duration(cfg.ScrubIntervalH) * time.Hour,
+		rate:        cfg.ScrubRateKiBps,
+		repair:      cfg.ScrubRepair,
+		model:       model,
+		timer:       time.NewTimer(time.Hour),
+		stop:        make(chan struct{}),
+		scrubNow:    make(chan chan error),
+		scrubSoon:   make(chan struct{}, 1),
+		mut:         sync.NewMutex(),
+	}
+}
+
+func (s *folderScrubber) Serve() {
+	if debug {
+		l.Debugln(s, "starting")
+		defer l.Debugln(s, "exiting")
+	}
+
+	defer s.timer.Stop()
+
+	// Continue the schedule from the last completed scrub, if any.
+	next := s.intv
+	if last := s.model.folderStatRef(s.folder).GetLastScrub(); !last.IsZero() {
+		next = last.Add(s.intv).Sub(time.Now())
+	}
+	if next < scrubStartupDelay {
+		next = scrubStartupDelay
+	}
+	s.timer.Reset(next)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+
+		case <-s.timer.C:
+			if err := s.scrub(); err != nil && err != errScrubStopped {
+				l.Infof("Scrubbing folder %q: %v", s.folder, err)
+			}
+			s.timer.Reset(s.intv)
+
+		case <-s.scrubSoon:
+			if err := s.scrub(); err != nil && err != errScrubStopped {
+				l.Infof("Scrubbing folder %q: %v", s.folder, err)
+			}
+			s.timer.Reset(s.intv)
+
+		case errc := <-s.scrubNow:
+			errc <- s.scrub()
+			s.timer.Reset(s.intv)
+		}
+	}
+}
+
+func (s *folderScrubber) Stop() {
+	close(s.stop)
+}
+
+func (s *folderScrubber) String() string {
+	return fmt.Sprintf("folderScrubber/%s@%p", s.folder, s)
+}
+
+// Scrub runs a scrub immediately and returns when it is complete.
+func (s *folderScrubber) Scrub() error {
+	errc := make(chan error)
+	select {
+	case s.scrubNow <- errc:
+		return <-errc
+	case <-s.stop:
+		return errScrubStopped
+	}
+}
+
+// Trigger schedules a scrub to start as soon as possible, without waiting
+// for it. Requests made while a scrub is already pending are coalesced into
+// it, and requests made while one is running are refused.
+func (s *folderScrubber) Trigger() error {
+	if s.Status().Running {
+		return errScrubRunning
+	}
+	select {
+	case s.scrubSoon <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Status returns a copy of the current scrub status.
+func (s *folderScrubber) Status() ScrubStatus {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	st := s.status
+	st.Mismatches = append([]ScrubMismatch(nil), s.status.Mismatches...)
+	return st
+}
+
+func (s *folderScrubber) scrub() error {
+	s.model.fmut.RLock()
+	fs, ok := s.model.folderFiles[s.folder]
+	runner := s.model.folderRunners[s.folder]
+	s.model.fmut.RUnlock()
+	if !ok || runner == nil {
+		return errors.New("no such folder")
+	}
+
+	if err := s.model.CheckFolderHealth(s.folder); err != nil {
+		return err
+	}
+
+	s.mut.Lock()
+	s.status = ScrubStatus{
+		Running: true,
+		Started: time.Now(),
+	}
+	s.mut.Unlock()
+
+	defer func() {
+		s.mut.Lock()
+		s.status.Running = false
+		s.status.Finished = time.Now()
+		s.mut.Unlock()
+	}()
+
+	l.Infoln("Starting scrub of folder", s.folder)
+
+	// Grab the list of names up front, so we don't hold a database snapshot
+	// open for the potentially very long duration of the scrub.
+	var names []string
+	fs.WithHaveTruncated(protocol.LocalDeviceID, func(fi db.FileIntf) bool {
+		f := fi.(db.FileInfoTruncated)
+		if !f.IsDirectory() && !f.IsSymlink() && !f.IsDeleted() && !f.IsInvalid() {
+			names = append(names, f.Name)
+		}
+		return true
+	})
+
+	var bucket *ratelimit.Bucket
+	if s.rate > 0 {
+		bucket = ratelimit.NewBucketWithRate(float64(1024*s.rate), int64(1024*s.rate))
+	}
+	mtimeRepo := db.NewVirtualMtimeRepo(s.model.db, s.folder)
+	buf := make([]byte, protocol.BlockSize)
+
+	for _, name := range names {
+		if err := s.waitIdle(runner); err != nil {
+			return err
+		}
+
+		f, ok := fs.Get(protocol.LocalDeviceID, name)
+		if !ok || f.IsDeleted() || f.IsInvalid() {
+			// Removed or changed since we started.
+			continue
+		}
+
+		bad, err := s.scrubFile(f, mtimeRepo, bucket, buf)
+		if err == errFileChanged || os.IsNotExist(err) {
+			// The scanner will pick up the change.
+			continue
+		} else if err != nil {
+			if debug {
+				l.Debugln(s, "scrubbing", name, err)
+			}
+			continue
+		}
+
+		s.mut.Lock()
+		s.status.Files++
+		s.status.Bytes += f.Size()
+		s.mut.Unlock()
+
+		if len(bad) > 0 {
+			s.reportMismatch(fs, f, bad, mtimeRepo)
+		}
+	}
+
+	s.model.folderStatRef(s.folder).ScrubCompleted()
+
+	s.mut.Lock()
+	files, mismatches := s.status.Files, len(s.status.Mismatches)
+	s.mut.Unlock()
+	l.Infof("Completed scrub of folder %q; %d files checked, %d with mismatches", s.folder, files, mismatches)
+
+	return nil
+}
+
+// waitIdle waits for the folder to be idle, as the scrub has lower priority
+// than scanning and syncing.
+func (s *folderScrubber) waitIdle(runner service) error {
+	for {
+		select {
+		case <-s.stop:
+			return errScrubStopped
+		default:
+		}
+
+		if state, _, _ := runner.getState(); state == FolderIdle {
+			return nil
+		}
+
+		select {
+		case <-s.stop:
+			return errScrubStopped
+		case <-time.After(scrubIdleCheckInterval):
+		}
+	}
+}
+
+// scrubFile reads the file and returns the indexes of the blocks that don't
+// match the hashes in the given file info. errFileChanged is returned if the
+// file on disk differs in size, modification time or permissions from what
+// is recorded in the index, as that is a regular change to be detected by the
+// scanner and not corruption.
+func (s *folderScrubber) scrubFile(f protocol.FileInfo, mtimeRepo *db.VirtualMtimeRepo, bucket *ratelimit.Bucket, buf []byte) ([]int, error) {
+	path := filepath.Join(s.dir, f.Name)
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	before, err := fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !s.unchanged(f, before, mtimeRepo) {
+		return nil, errFileChanged
+	}
+
+	var r io.Reader = fd
+	if bucket != nil {
+		r = ratelimit.Reader(fd, bucket)
+	}
+
+	var bad []int
+	for i, block := range f.Blocks {
+		select {
+		case <-s.stop:
+			return nil, errScrubStopped
+		default:
+		}
+
+		bs := buf[:block.Size]
+		if _, err := io.ReadFull(r, bs); err != nil {
+			return nil, err
+		}
+		if _, err := scanner.VerifyBuffer(bs, block, f.HashAlgorithm()); err != nil {
+			bad = append(bad, i)
+		}
+	}
+
+	// A file that was modified while we were reading it will look corrupt,
+	// but isn't.
+	after, err := osutil.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !s.unchanged(f, after, mtimeRepo) {
+		return nil, errFileChanged
+	}
+
+	return bad, nil
+}
+
+func (s *folderScrubber) unchanged(f protocol.FileInfo, info os.FileInfo, mtimeRepo *db.VirtualMtimeRepo) bool {
+	mtime := mtimeRepo.GetMtime(f.Name, info.ModTime())
+	permUnchanged := s.ignorePerms || !f.HasPermissionBits() || scanner.PermsEqual(f.Flags, uint32(info.Mode()))
+	return info.Mode().IsRegular() && permUnchanged && info.Size() == f.Size() && mtime.Unix() == f.Modified
+}
+
+func (s *folderScrubber) reportMismatch(fs *db.FileSet, f protocol.FileInfo, bad []int, mtimeRepo *db.VirtualMtimeRepo) {
+	l.Warnf("Scrub: %d blocks of %q in folder %q do not match the index", len(bad), f.Name, s.folder)
+
+	mm := ScrubMismatch{
+		File:     f.Name,
+		Blocks:   bad,
+		Detected: time.Now(),
+	}
+
+	if s.repair {
+		if err := s.repairFile(fs, f, bad, mtimeRepo); err != nil {
+			l.Infof("Scrub: repairing %q in folder %q: %v", f.Name, s.folder, err)
+			mm.Error = err.Error()
+		} else {
+			l.Infof("Scrub: repaired %q in folder %q", f.Name, s.folder)
+			mm.Repaired = true
+		}
+	}
+
+	s.mut.Lock()
+	if len(s.status.Mismatches) < maxScrubMismatches {
+		s.status.Mismatches = append(s.status.Mismatches, mm)
+	}
+	s.mut.Unlock()
+
+	events.Default.Log(events.FileCorrupted, map[string]interface{}{
+		"folder":   s.folder,
+		"item":     f.Name,
+		"blocks":   bad,
+		"repaired": mm.Repaired,
+	})
+}
+
+// repairFile fetches the given blocks from devices that announce the same
+// version of the file as we have, and writes them into place. The
+// modification time is restored afterwards so that the scanner doesn't see
+// the repair as a local change.
+func (s *folderScrubber) repairFile(fs *db.FileSet, f protocol.FileInfo, bad []int, mtimeRepo *db.VirtualMtimeRepo) error {
+	var peers []protocol.DeviceID
+	if gf, ok := fs.GetGlobal(f.Name); ok && gf.Version.Equal(f.Version) {
+		for _, dev := range fs.Availability(f.Name) {
+			if dev != s.model.id && s.model.ConnectedTo(dev) {
+				peers = append(peers, dev)
+			}
+		}
+	}
+	if len(peers) == 0 {
+		return errNoRepairPeer
+	}
+
+	// The block offsets aren't stored in the index.
+	scanner.PopulateOffsets(f.Blocks)
+
+	path := filepath.Join(s.dir, f.Name)
+	info, err := osutil.Lstat(path)
+	if err != nil {
+		return err
+	}
+	fd, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	var repairErr error
+nextBlock:
+	for _, i := range bad {
+		block := f.Blocks[i]
+		for _, dev := range peers {
+			buf, err := s.model.requestGlobal(dev, s.folder, f.Name, block.Offset, int(block.Size), block.Hash, 0, nil)
+			if err != nil {
+				continue
+			}
+			if _, err := scanner.VerifyBuffer(buf, block, f.HashAlgorithm()); err != nil {
+				continue
+			}
+			if _, err := fd.WriteAt(buf, block.Offset); err != nil {
+				repairErr = err
+				break nextBlock
+			}
+			continue nextBlock
+		}
+		repairErr = fmt.Errorf("block %d not available from any device", i)
+		break
+	}
+
+	if err := fd.Close(); err != nil && repairErr == nil {
+		repairErr = err
+	}
+
+	// Restore the modification time we had, keeping the virtual mtime
+	// repository in sync on platforms where we can't set it precisely.
+	mtime := mtimeRepo.GetMtime(f.Name, info.ModTime())
+	if err := os.Chtimes(path, mtime, mtime); err == nil {
+		if newInfo, err := osutil.Lstat(path); err == nil && !newInfo.ModTime().Equal(mtime) {
+			mtimeRepo.UpdateMtime(f.Name, newInfo.ModTime(), mtime)
+		}
+	}
+
+	return repairErr
+}
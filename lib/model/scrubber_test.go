@@ -0,0 +1,262 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestScrubDetectsCorruption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Three blocks, of which we'll corrupt the second.
+	data := make([]byte, 2*protocol.BlockSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	path := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "intact"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fcfg := config.FolderConfiguration{
+		ID:             "scrub",
+		RawPath:        dir,
+		ScrubIntervalH: 24,
+	}
+	cfg := config.Wrap("/tmp/test", config.Configuration{
+		Folders: []config.FolderConfiguration{fcfg},
+	})
+
+	m := NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+	m.StartFolderRO("scrub")
+	m.ServeBackground()
+	defer m.Stop()
+	if err := m.ScanFolder("scrub"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flip a byte without changing size or modification time, as bit rot
+	// would.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.WriteAt([]byte{^data[protocol.BlockSize+1]}, protocol.BlockSize+1); err != nil {
+		t.Fatal(err)
+	}
+	fd.Close()
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ScrubFolder("scrub"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := m.ScrubStatus("scrub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Running || status.Files != 2 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if len(status.Mismatches) != 1 {
+		t.Fatalf("expected one mismatch, got %+v", status.Mismatches)
+	}
+	mm := status.Mismatches[0]
+	if mm.File != "file" || len(mm.Blocks) != 1 || mm.Blocks[0] != 1 || mm.Repaired {
+		t.Errorf("unexpected mismatch %+v", mm)
+	}
+
+	if time.Since(m.folderStatRef("scrub").GetLastScrub()) > time.Minute {
+		t.Error("last scrub time not recorded")
+	}
+}
+
+func TestScrubIgnoresModifiedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(path, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fcfg := config.FolderConfiguration{
+		ID:             "scrub",
+		RawPath:        dir,
+		ScrubIntervalH: 24,
+	}
+	cfg := config.Wrap("/tmp/test", config.Configuration{
+		Folders: []config.FolderConfiguration{fcfg},
+	})
+
+	m := NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+	m.StartFolderRO("scrub")
+	m.ServeBackground()
+	defer m.Stop()
+	if err := m.ScanFolder("scrub"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A regular change is for the scanner to handle, not corruption.
+	if err := ioutil.WriteFile(path, []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(path, future, future)
+
+	if err := m.ScrubFolder("scrub"); err != nil {
+		t.Fatal(err)
+	}
+	status, _ := m.ScrubStatus("scrub")
+	if len(status.Mismatches) != 0 {
+		t.Errorf("modified file reported as corrupt: %+v", status.Mismatches)
+	}
+}
+
+func TestScrubTriggerCoalesces(t *testing.T) {
+	s := newFolderScrubber(nil, config.FolderConfiguration{ID: "scrub", ScrubIntervalH: 24})
+
+	for i := 0; i < 10; i++ {
+		if err := s.Trigger(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.scrubSoon) != 1 {
+		t.Errorf("expected one pending scrub, got %d", len(s.scrubSoon))
+	}
+
+	s.status.Running = true
+	if err := s.Trigger(); err != errScrubRunning {
+		t.Errorf("unexpected error %v while running", err)
+	}
+}
+
+func TestScrubRepairsFromPeer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Three blocks with different contents, of which we'll corrupt the
+	// second.
+	data := make([]byte, 2*protocol.BlockSize+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	path := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fcfg := config.FolderConfiguration{
+		ID:             "scrub",
+		RawPath:        dir,
+		ScrubIntervalH: 24,
+		ScrubRepair:    true,
+		Devices:        []config.FolderDeviceConfiguration{{DeviceID: device1}},
+	}
+	cfg := config.Wrap("/tmp/test", config.Configuration{
+		Folders: []config.FolderConfiguration{fcfg},
+		Devices: []config.DeviceConfiguration{{DeviceID: device1}},
+	})
+
+	m := NewModel(cfg, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(fcfg)
+	m.StartFolderRO("scrub")
+	m.ServeBackground()
+	defer m.Stop()
+	if err := m.ScanFolder("scrub"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The peer has the same version of the file, and serves the good
+	// second block.
+	f, ok := m.CurrentFolderFile("scrub", "file")
+	if !ok {
+		t.Fatal("file not in index")
+	}
+	m.AddConnection(Connection{
+		&net.TCPConn{},
+		FakeConnection{id: device1, requestData: data[protocol.BlockSize : 2*protocol.BlockSize]},
+		ConnectionTypeDirectAccept,
+	})
+	m.Index(device1, "scrub", []protocol.FileInfo{f}, 0, nil)
+
+	// Flip a byte in the second block, keeping the mtime as bit rot would.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.WriteAt([]byte{^data[protocol.BlockSize+1]}, protocol.BlockSize+1); err != nil {
+		t.Fatal(err)
+	}
+	fd.Close()
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ScrubFolder("scrub"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, _ := m.ScrubStatus("scrub")
+	if len(status.Mismatches) != 1 {
+		t.Fatalf("expected one mismatch, got %+v", status.Mismatches)
+	}
+	if mm := status.Mismatches[0]; mm.File != "file" || !mm.Repaired {
+		t.Errorf("unexpected mismatch %+v", mm)
+	}
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, data) {
+		t.Error("file content not repaired")
+	}
+	newInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newInfo.ModTime().Equal(info.ModTime()) {
+		t.Errorf("mtime changed by repair: %v != %v", newInfo.ModTime(), info.ModTime())
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/fnmatch"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// A SearchFilter selects files by a property they either have or not.
+type SearchFilter int
+
+const (
+	SearchAny     SearchFilter = iota // files with or without the property
+	SearchOnly                        // only files with the property
+	SearchExclude                     // only files without the property
+)
+
+func (f SearchFilter) matches(v bool) bool {
+	switch f {
+	case SearchOnly:
+		return v
+	case SearchExclude:
+		return !v
+	default:
+		return true
+	}
+}
+
+// SearchOptions are the criteria for a search of the global index of a
+// folder. The zero value matches every file.
+type SearchOptions struct {
+	// Query is a glob pattern, or a regular expression if Regex is set,
+	// matched against the path of the file relative to the folder root. A
+	// glob without path separators is matched against the file name only,
+	// so that "*.jpg" finds images in all directories.
+	Query string
+	Regex bool
+
+	// Prefix restricts the search to the given directory.
+	Prefix string
+
+	// MinSize and MaxSize are inclusive bounds on the size of the file,
+	// unbounded when zero.
+	MinSize, MaxSize int64
+
+	// ModifiedAfter and ModifiedBefore are exclusive bounds on the
+	// modification time of the file, unbounded when zero.
+	ModifiedAfter, ModifiedBefore time.Time
+
+	// Deleted filters on whether the global version is a deletion, and
+	// Available on whether the global version is present locally or on a
+	// connected device.
+	Deleted   SearchFilter
+	Available SearchFilter
+}
+
+// matcher returns a function matching the query against file names.
+func (o SearchOptions) matcher() (func(name string) bool, error) {
+	if o.Query == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	if o.Regex {
+		exp, err := regexp.Compile(o.Query)
+		if err != nil {
+			return nil, err
+		}
+		return exp.MatchString, nil
+	}
+
+	exp, err := fnmatch.Convert(o.Query, fnmatch.PathName)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.ContainsAny(o.Query, `/`+string(filepath.Separator)) {
+		return func(name string) bool {
+			return exp.MatchString(filepath.Base(name))
+		}, nil
+	}
+	return exp.MatchString, nil
+}
+
+// SearchGlobal returns the given page of the files in the global index of
+// the folder that match the options, along with the total number of
+// matches.
+func (m *Model) SearchGlobal(folder string, opts SearchOptions, page, perpage int) ([]db.FileInfoTruncated, int, error) {
+	match, err := opts.matcher()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m.fmut.RLock()
+	files, ok := m.folderFiles[folder]
+	m.fmut.RUnlock()
+	if !ok {
+		return nil, 0, errors.New("no such folder")
+	}
+
+	var connected map[protocol.DeviceID]struct{}
+	if opts.Available != SearchAny {
+		m.pmut.RLock()
+		connected = make(map[protocol.DeviceID]struct{}, len(m.conn)+1)
+		for device := range m.conn {
+			connected[device] = struct{}{}
+		}
+		m.pmut.RUnlock()
+		connected[protocol.LocalDeviceID] = struct{}{}
+	}
+
+	prefix := osutil.NativeFilename(opts.Prefix)
+	sep := string(filepath.Separator)
+	if prefix != "" && !strings.HasSuffix(prefix, sep) {
+		prefix = prefix + sep
+	}
+
+	skip := (page - 1) * perpage
+	total := 0
+	res := make([]db.FileInfoTruncated, 0, 32)
+
+	files.WithPrefixedGlobalTruncated(prefix, func(fi db.FileIntf) bool {
+		f := fi.(db.FileInfoTruncated)
+
+		if f.IsInvalid() || !opts.Deleted.matches(f.IsDeleted()) {
+			return true
+		}
+		if size := f.Size(); size < opts.MinSize || (opts.MaxSize > 0 && size > opts.MaxSize) {
+			return true
+		}
+		modified := time.Unix(f.Modified, 0)
+		if (!opts.ModifiedAfter.IsZero() && !modified.After(opts.ModifiedAfter)) ||
+			(!opts.ModifiedBefore.IsZero() && !modified.Before(opts.ModifiedBefore)) {
+			return true
+		}
+		if !match(f.Name) {
+			return true
+		}
+		if opts.Available != SearchAny {
+			available := false
+			for _, device := range files.Availability(f.Name) {
+				if _, ok := connected[device]; ok {
+					available = true
+					break
+				}
+			}
+			if !opts.Available.matches(available) {
+				return true
+			}
+		}
+
+		total++
+		if total > skip && len(res) < perpage {
+			res = append(res, f)
+		}
+		return true
+	})
+
+	return res, total, nil
+}
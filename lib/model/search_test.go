@@ -0,0 +1,123 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func setupSearchModel() *Model {
+	m := NewModel(defaultConfig, protocol.LocalDeviceID, "device", "syncthing", "dev", db.OpenMemory())
+	m.AddFolder(defaultFolderConfig)
+
+	version := protocol.Vector{{ID: 42, Value: 1}}
+	block := func(size int32) []protocol.BlockInfo {
+		return []protocol.BlockInfo{{Offset: 0, Size: size}}
+	}
+	m.Index(device1, "default", []protocol.FileInfo{
+		{Name: "photos", Flags: protocol.FlagDirectory, Modified: 1000, Version: version},
+		{Name: filepath.Join("photos", "a.jpg"), Modified: 1000, Version: version, Blocks: block(100)},
+		{Name: filepath.Join("photos", "b.png"), Modified: 2000, Version: version, Blocks: block(200)},
+		{Name: filepath.Join("photos", "notes.txt"), Modified: 3000, Version: version, Blocks: block(300)},
+		{Name: "c.jpg", Modified: 4000, Version: version, Blocks: block(400)},
+		{Name: "old.jpg", Flags: protocol.FlagDeleted, Modified: 5000, Version: version},
+	}, 0, nil)
+
+	return m
+}
+
+func searchNames(t *testing.T, m *Model, opts SearchOptions) []string {
+	files, total, err := m.SearchGlobal("default", opts, 1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != len(files) {
+		t.Errorf("total %d != returned %d", total, len(files))
+	}
+	names := []string{}
+	for _, f := range files {
+		names = append(names, filepath.ToSlash(f.Name))
+	}
+	return names
+}
+
+func TestSearchGlobal(t *testing.T) {
+	m := setupSearchModel()
+
+	cases := []struct {
+		opts  SearchOptions
+		names []string
+	}{
+		{SearchOptions{Query: "*.jpg", Deleted: SearchExclude}, []string{"c.jpg", "photos/a.jpg"}},
+		{SearchOptions{Query: "*.jpg"}, []string{"c.jpg", "old.jpg", "photos/a.jpg"}},
+		{SearchOptions{Query: "*.jpg", Deleted: SearchOnly}, []string{"old.jpg"}},
+		{SearchOptions{Query: "photos/*"}, []string{"photos/a.jpg", "photos/b.png", "photos/notes.txt"}},
+		{SearchOptions{Query: `(?i)^photos.*\.PNG$`, Regex: true, Deleted: SearchExclude}, []string{"photos/b.png"}},
+		{SearchOptions{Prefix: "photos"}, []string{"photos/a.jpg", "photos/b.png", "photos/notes.txt"}},
+		{SearchOptions{MinSize: 200, MaxSize: 300}, []string{"photos/b.png", "photos/notes.txt"}},
+		{SearchOptions{ModifiedAfter: time.Unix(1000, 0), ModifiedBefore: time.Unix(4000, 0)}, []string{"photos/b.png", "photos/notes.txt"}},
+	}
+
+	for i, tc := range cases {
+		if names := searchNames(t, m, tc.opts); !reflect.DeepEqual(names, tc.names) {
+			t.Errorf("%d: %v != expected %v", i, names, tc.names)
+		}
+	}
+
+	if _, _, err := m.SearchGlobal("default", SearchOptions{Query: "(", Regex: true}, 1, 100); err == nil {
+		t.Error("an invalid regexp should be an error")
+	}
+	if _, _, err := m.SearchGlobal("nonexistent", SearchOptions{}, 1, 100); err == nil {
+		t.Error("searching a nonexistent folder should be an error")
+	}
+}
+
+func TestSearchGlobalPaging(t *testing.T) {
+	m := setupSearchModel()
+
+	opts := SearchOptions{Deleted: SearchExclude}
+	files, total, err := m.SearchGlobal("default", opts, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 {
+		t.Errorf("total %d != expected 5", total)
+	}
+	if len(files) != 2 || filepath.ToSlash(files[0].Name) != "photos/a.jpg" {
+		t.Errorf("unexpected second page %v", files)
+	}
+}
+
+func TestSearchGlobalAvailable(t *testing.T) {
+	m := setupSearchModel()
+
+	opts := SearchOptions{Query: "c.jpg", Available: SearchOnly}
+	if names := searchNames(t, m, opts); len(names) != 0 {
+		t.Errorf("file should not be available without a connection, %v", names)
+	}
+
+	m.AddConnection(Connection{
+		&net.TCPConn{},
+		FakeConnection{id: device1},
+		ConnectionTypeDirectAccept,
+	})
+	if names := searchNames(t, m, opts); len(names) != 1 {
+		t.Errorf("file should be available from the connected device, %v", names)
+	}
+
+	opts.Available = SearchExclude
+	if names := searchNames(t, m, opts); len(names) != 0 {
+		t.Errorf("file should not be unavailable, %v", names)
+	}
+}
@@ -122,6 +122,15 @@ func (s *sharedPullerState) tempFile() (io.WriterAt, error) {
 		return nil, err
 	}
 
+	// Extend the file to its final size up front. Blocks of all zeros are
+	// then never written, leaving the file sparse where the filesystem
+	// supports it.
+	if err := fd.Truncate(s.file.Size()); err != nil {
+		fd.Close()
+		s.failLocked("dst truncate", err)
+		return nil, err
+	}
+
 	// Same fd will be used by all writers
 	s.fd = fd
 
@@ -0,0 +1,94 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The folder option announcing a subscribed subtree. There is one option per
+// subtree; a folder without any is subscribed to in full.
+const subscriptionOption = "subscription"
+
+// The number of subscriptions that fit in the folder options, leaving room
+// for the others.
+const maxAnnouncedSubscriptions = 60
+
+// The maximum length of an option value in the protocol.
+const maxOptionValueLen = 1024
+
+// subscriptions are the subtrees of a folder that a device wants, as native
+// paths relative to the folder root. An empty list means the whole folder.
+type subscriptions []string
+
+func newSubscriptions(paths []string) subscriptions {
+	var subs subscriptions
+	for _, path := range paths {
+		path = filepath.Clean(filepath.FromSlash(path))
+		path = strings.Trim(path, string(filepath.Separator))
+		if path == "" || path == "." {
+			// The root, meaning everything.
+			return nil
+		}
+		subs = append(subs, path)
+	}
+	return subs
+}
+
+// subscriptionsFromOptions returns the subscriptions announced in the folder
+// options of a cluster config.
+func subscriptionsFromOptions(folder protocol.Folder) subscriptions {
+	var paths []string
+	for _, opt := range folder.Options {
+		if opt.Key == subscriptionOption {
+			paths = append(paths, opt.Value)
+		}
+	}
+	return newSubscriptions(paths)
+}
+
+// options returns the folder options announcing the subscriptions. Too many
+// subscriptions to announce are not announced at all, meaning the other
+// device will consider us interested in everything.
+func (s subscriptions) options() []protocol.Option {
+	if len(s) > maxAnnouncedSubscriptions {
+		l.Infof("Not announcing %d subscribed subtrees, the limit is %d", len(s), maxAnnouncedSubscriptions)
+		return nil
+	}
+	opts := make([]protocol.Option, len(s))
+	for i, path := range s {
+		if len(path) > maxOptionValueLen {
+			l.Infof("Not announcing subscribed subtrees, %q is too long", path)
+			return nil
+		}
+		opts[i] = protocol.Option{
+			Key:   subscriptionOption,
+			Value: filepath.ToSlash(path),
+		}
+	}
+	return opts
+}
+
+// covers returns true if the named file should be synced, which is the case
+// if it is in one of the subscribed subtrees, or is one of the directories
+// leading to one.
+func (s subscriptions) covers(name string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, sub := range s {
+		if name == sub ||
+			strings.HasPrefix(name, sub+string(filepath.Separator)) ||
+			strings.HasPrefix(sub, name+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
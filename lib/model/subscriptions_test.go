@@ -0,0 +1,61 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestSubscriptionsCovers(t *testing.T) {
+	subs := newSubscriptions([]string{"projects/alpha/", "/docs"})
+
+	var tests = []struct {
+		name   string
+		covers bool
+	}{
+		{"projects", true},
+		{filepath.Join("projects", "alpha"), true},
+		{filepath.Join("projects", "alpha", "file"), true},
+		{filepath.Join("projects", "alphabet"), false},
+		{filepath.Join("projects", "beta"), false},
+		{"docs", true},
+		{filepath.Join("docs", "index.html"), true},
+		{"other", false},
+	}
+
+	for _, tc := range tests {
+		if c := subs.covers(tc.name); c != tc.covers {
+			t.Errorf("%q: covers %v != %v", tc.name, c, tc.covers)
+		}
+	}
+
+	for _, paths := range [][]string{nil, {"/"}, {"alpha", "."}} {
+		if subs := newSubscriptions(paths); !subs.covers("anything") {
+			t.Errorf("%v should cover the whole folder", paths)
+		}
+	}
+}
+
+func TestSubscriptionsOptions(t *testing.T) {
+	subs := newSubscriptions([]string{"projects/alpha", "docs"})
+	folder := protocol.Folder{
+		ID:      "default",
+		Options: append([]protocol.Option{{Key: "hashAlgorithm", Value: "sha256"}}, subs.options()...),
+	}
+
+	parsed := subscriptionsFromOptions(folder)
+	if len(parsed) != 2 || parsed[0] != filepath.Join("projects", "alpha") || parsed[1] != "docs" {
+		t.Errorf("Incorrect subscriptions %v", parsed)
+	}
+
+	if opts := subscriptions(make([]string, maxAnnouncedSubscriptions+1)).options(); opts != nil {
+		t.Error("Too many subscriptions should not be announced")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package osutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// IsCaseInsensitive returns true if the file system holding the given path
+// treats names that differ only in case as the same. If the path doesn't
+// exist, the closest existing parent directory is checked instead. Nothing
+// is written; if there is no name with letters in it to test with, the file
+// system is assumed to be case sensitive.
+func IsCaseInsensitive(path string) bool {
+	path = filepath.Clean(path)
+	for {
+		if info, err := os.Lstat(path); err == nil {
+			if swapped := swapCase(filepath.Base(path)); swapped != filepath.Base(path) {
+				other, err := os.Lstat(filepath.Join(filepath.Dir(path), swapped))
+				return err == nil && os.SameFile(info, other)
+			}
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+		path = parent
+	}
+}
+
+// CaseConflict checks whether any component of name, relative to root,
+// exists on disk under a name that differs only in case. If so, the
+// conflicting on disk name, relative to root, is returned. Components that
+// don't exist at all are not conflicts.
+func CaseConflict(root, name string) (string, bool) {
+	return NewCaseCache(root).Conflict(name)
+}
+
+// A CaseCache answers CaseConflict queries for names below the same root,
+// reading each directory only once. Names created after a directory was read
+// must be added to keep the answers correct.
+type CaseCache struct {
+	root string
+	dirs map[string]caseDir
+}
+
+// caseDir holds the names in a directory, and the same names keyed by their
+// lower case form.
+type caseDir struct {
+	exact  map[string]struct{}
+	folded map[string]string
+}
+
+func NewCaseCache(root string) *CaseCache {
+	return &CaseCache{
+		root: root,
+		dirs: make(map[string]caseDir),
+	}
+}
+
+// Conflict is like CaseConflict, for a name below the root of the cache.
+func (c *CaseCache) Conflict(name string) (string, bool) {
+	dir := ""
+	parts := strings.Split(filepath.Clean(name), string(filepath.Separator))
+	for i, part := range parts {
+		names := c.dir(dir)
+		if _, ok := names.exact[part]; ok {
+			dir = filepath.Join(dir, part)
+			continue
+		}
+		if fold, ok := names.folded[strings.ToLower(part)]; ok {
+			return filepath.Join(append(parts[:i:i], fold)...), true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// Add records that name, and the directories leading to it, now exist.
+func (c *CaseCache) Add(name string) {
+	dir := ""
+	for _, part := range strings.Split(filepath.Clean(name), string(filepath.Separator)) {
+		names := c.dir(dir)
+		names.exact[part] = struct{}{}
+		if _, ok := names.folded[strings.ToLower(part)]; !ok {
+			names.folded[strings.ToLower(part)] = part
+		}
+		dir = filepath.Join(dir, part)
+	}
+}
+
+// dir returns the names in the directory, relative to the root. A directory
+// that can't be read is taken to be empty.
+func (c *CaseCache) dir(dir string) caseDir {
+	if names, ok := c.dirs[dir]; ok {
+		return names
+	}
+
+	names := caseDir{
+		exact:  make(map[string]struct{}),
+		folded: make(map[string]string),
+	}
+	if fd, err := os.Open(filepath.Join(c.root, dir)); err == nil {
+		list, _ := fd.Readdirnames(-1)
+		fd.Close()
+		for _, n := range list {
+			names.exact[n] = struct{}{}
+			if _, ok := names.folded[strings.ToLower(n)]; !ok {
+				names.folded[strings.ToLower(n)] = n
+			}
+		}
+	}
+	c.dirs[dir] = names
+	return names
+}
+
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
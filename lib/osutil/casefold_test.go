@@ -0,0 +1,122 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package osutil_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/osutil"
+)
+
+func TestCaseConflict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "casefold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "Docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "Docs", "Report.docx"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		existing string
+		conflict bool
+	}{
+		{filepath.Join("Docs", "Report.docx"), "", false},
+		{filepath.Join("Docs", "Other.docx"), "", false},
+		{filepath.Join("Other", "Report.docx"), "", false},
+		{filepath.Join("Docs", "report.docx"), filepath.Join("Docs", "Report.docx"), true},
+		{filepath.Join("docs", "Report.docx"), "Docs", true},
+		{"DOCS", "Docs", true},
+	}
+
+	for _, tc := range cases {
+		existing, conflict := osutil.CaseConflict(dir, tc.name)
+		if existing != tc.existing || conflict != tc.conflict {
+			t.Errorf("CaseConflict(%q) = %q, %v; expected %q, %v", tc.name, existing, conflict, tc.existing, tc.conflict)
+		}
+	}
+}
+
+func TestCaseCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "casefold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "Report.docx"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := osutil.NewCaseCache(dir)
+	if _, conflict := c.Conflict(filepath.Join("new", "file")); conflict {
+		t.Error("unexpected conflict for a new name")
+	}
+
+	// The directory was read once; later changes on disk are not seen.
+	if err := ioutil.WriteFile(filepath.Join(dir, "Other.docx"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, conflict := c.Conflict("other.docx"); conflict {
+		t.Error("directory listing should be cached")
+	}
+
+	// Added names conflict with others equal to them apart from case.
+	c.Add(filepath.Join("new", "file"))
+	cases := []struct {
+		name     string
+		existing string
+	}{
+		{"report.docx", "Report.docx"},
+		{"NEW", "new"},
+		{filepath.Join("new", "FILE"), filepath.Join("new", "file")},
+	}
+	for _, tc := range cases {
+		if existing, conflict := c.Conflict(tc.name); !conflict || existing != tc.existing {
+			t.Errorf("Conflict(%q) = %q, %v; expected %q, true", tc.name, existing, conflict, tc.existing)
+		}
+	}
+	if _, conflict := c.Conflict(filepath.Join("new", "file")); conflict {
+		t.Error("an added name should not conflict with itself")
+	}
+}
+
+func TestIsCaseInsensitive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "casefold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Whatever the answer, it must be the same for a directory and for a
+	// not yet existing path within it.
+	expected := osutil.IsCaseInsensitive(dir)
+	if res := osutil.IsCaseInsensitive(filepath.Join(dir, "does", "not", "exist")); res != expected {
+		t.Errorf("nonexistent path gave %v, expected %v", res, expected)
+	}
+
+	// Having both names around makes it plain that case matters.
+	if err := os.Mkdir(filepath.Join(dir, "Sync"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sYNC"), 0755); err == nil {
+		if osutil.IsCaseInsensitive(filepath.Join(dir, "Sync")) {
+			t.Error("file system with distinct Sync and sYNC reported as case insensitive")
+		}
+	} else if !osutil.IsCaseInsensitive(filepath.Join(dir, "Sync")) {
+		t.Error("file system refusing sYNC next to Sync reported as case sensitive")
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build windows
+
+package osutil
+
+import "os"
+
+// InodeOf returns the inode number of the file described by info, and
+// whether it could be determined. The file index is not available from an
+// os.FileInfo on Windows, so this always fails.
+func InodeOf(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
+
+// DeviceOf returns the number of the device containing the file described by
+// info, and whether it could be determined. As with InodeOf, this always
+// fails on Windows.
+func DeviceOf(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2015 The Protocol Authors.
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/minio/blake2b-simd"
+)
+
+// HashAlgorithm is the hash function used to compute the block hashes of a
+// file. It is recorded in the FileInfo flags (see FlagHashAlgorithmMask),
+// with the zero value being SHA-256 so that files announced by older
+// devices are interpreted correctly.
+type HashAlgorithm int
+
+const (
+	SHA256 HashAlgorithm = iota
+	BLAKE2b
+)
+
+// The hash algorithm occupies four bits of the FileInfo flags, starting at
+// this bit.
+const hashAlgorithmShift = 18
+
+// HashAlgorithms lists all hash algorithms we are able to compute and
+// verify.
+var HashAlgorithms = []HashAlgorithm{SHA256, BLAKE2b}
+
+func (h HashAlgorithm) String() string {
+	switch h {
+	case SHA256:
+		return "sha256"
+	case BLAKE2b:
+		return "blake2b"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(h))
+	}
+}
+
+// IsKnown returns true if the hash algorithm is one we can compute.
+func (h HashAlgorithm) IsKnown() bool {
+	return h == SHA256 || h == BLAKE2b
+}
+
+// New returns a new hash.Hash computing the algorithm. All algorithms give
+// 32 byte hashes. New panics if the algorithm is unknown.
+func (h HashAlgorithm) New() hash.Hash {
+	switch h {
+	case SHA256:
+		return sha256.New()
+	case BLAKE2b:
+		return blake2b.New256()
+	default:
+		panic("bug: unknown hash algorithm " + h.String())
+	}
+}
+
+// Flags returns the FileInfo flag bits representing the hash algorithm.
+func (h HashAlgorithm) Flags() uint32 {
+	return (uint32(h) << hashAlgorithmShift) & FlagHashAlgorithmMask
+}
+
+func (h HashAlgorithm) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText sets the hash algorithm from its name. An empty name means
+// SHA-256, the default.
+func (h *HashAlgorithm) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "", "sha256":
+		*h = SHA256
+	case "blake2b":
+		*h = BLAKE2b
+	default:
+		return fmt.Errorf("unknown hash algorithm %q", bs)
+	}
+	return nil
+}
+
+// ParseHashAlgorithms parses a comma separated list of hash algorithm names,
+// as sent in the "hashAlgorithms" cluster config option. Unknown names are
+// skipped. An empty string means SHA-256 only, as that is what devices
+// predating the option support.
+func ParseHashAlgorithms(s string) []HashAlgorithm {
+	if s == "" {
+		return []HashAlgorithm{SHA256}
+	}
+	var res []HashAlgorithm
+	for _, name := range strings.Split(s, ",") {
+		for _, h := range HashAlgorithms {
+			if name == h.String() {
+				res = append(res, h)
+			}
+		}
+	}
+	return res
+}
+
+// FormatHashAlgorithms returns the comma separated list of names of the given
+// hash algorithms.
+func FormatHashAlgorithms(hs []HashAlgorithm) string {
+	names := make([]string, len(hs))
+	for i, h := range hs {
+		names[i] = h.String()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2015 The Protocol Authors.
+
+package protocol
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashAlgorithmUnmarshalText(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		algo HashAlgorithm
+	}{
+		{"", SHA256},
+		{"sha256", SHA256},
+		{"blake2b", BLAKE2b},
+	} {
+		var h HashAlgorithm
+		if err := h.UnmarshalText([]byte(tc.name)); err != nil || h != tc.algo {
+			t.Errorf("%q gave %v, %v; expected %v", tc.name, h, err, tc.algo)
+		}
+	}
+
+	for _, name := range []string{"blake2", "SHA256", "md5"} {
+		var h HashAlgorithm
+		if err := h.UnmarshalText([]byte(name)); err == nil {
+			t.Errorf("%q should be an unknown hash algorithm", name)
+		}
+	}
+}
+
+func TestHashAlgorithmNew(t *testing.T) {
+	// BLAKE2b-256 of the empty string.
+	expected := "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8"
+	if sum := hex.EncodeToString(BLAKE2b.New().Sum(nil)); sum != expected {
+		t.Errorf("unexpected BLAKE2b hash %s", sum)
+	}
+}
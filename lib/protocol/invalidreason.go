@@ -0,0 +1,51 @@
+// Copyright (C) 2015 The Protocol Authors.
+
+package protocol
+
+import "fmt"
+
+// InvalidReason tells why a file has been marked invalid by the device
+// announcing it, when it's something other than the file matching an ignore
+// pattern. It is recorded in the FileInfo flags (see FlagInvalidReasonMask)
+// next to FlagInvalid.
+type InvalidReason int
+
+const (
+	InvalidReasonNone         InvalidReason = iota
+	InvalidReasonCharacters                 // The name contains characters not allowed on some platform
+	InvalidReasonReservedName               // The name is reserved on some platform
+	InvalidReasonTrailingChar               // The name ends in a character not allowed there on some platform
+	InvalidReasonTooLong                    // The name or path is too long for some platform
+	InvalidReasonTooLarge                   // The file is larger than the folder allows
+	InvalidReasonTooRecent                  // The file was modified too recently, and may still be written to
+)
+
+// The invalid reason occupies four bits of the FileInfo flags, starting at
+// this bit.
+const invalidReasonShift = 22
+
+func (r InvalidReason) String() string {
+	switch r {
+	case InvalidReasonNone:
+		return "none"
+	case InvalidReasonCharacters:
+		return "name contains disallowed characters"
+	case InvalidReasonReservedName:
+		return "name is reserved"
+	case InvalidReasonTrailingChar:
+		return "name ends with a space or period"
+	case InvalidReasonTooLong:
+		return "name or path is too long"
+	case InvalidReasonTooLarge:
+		return "file is larger than the folder size limit"
+	case InvalidReasonTooRecent:
+		return "file was modified too recently"
+	default:
+		return fmt.Sprintf("unknown reason (%d)", int(r))
+	}
+}
+
+// Flags returns the FileInfo flag bits representing the reason.
+func (r InvalidReason) Flags() uint32 {
+	return (uint32(r) << invalidReasonShift) & FlagInvalidReasonMask
+}
@@ -59,6 +59,17 @@ func (f FileInfo) HasPermissionBits() bool {
 	return f.Flags&FlagNoPermBits == 0
 }
 
+// HashAlgorithm returns the algorithm used to compute the block hashes.
+func (f FileInfo) HashAlgorithm() HashAlgorithm {
+	return HashAlgorithm((f.Flags & FlagHashAlgorithmMask) >> hashAlgorithmShift)
+}
+
+// InvalidReason returns why the file is invalid, if it is for some other
+// reason than being ignored.
+func (f FileInfo) InvalidReason() InvalidReason {
+	return InvalidReason((f.Flags & FlagInvalidReasonMask) >> invalidReasonShift)
+}
+
 // WinsConflict returns true if "f" is the one to choose when it is in
 // conflict with "other".
 func (f FileInfo) WinsConflict(other FileInfo) bool {
@@ -132,6 +143,15 @@ type Folder struct {
 	Options []Option // max:64
 }
 
+func (f *Folder) GetOption(key string) string {
+	for _, option := range f.Options {
+		if option.Key == key {
+			return option.Value
+		}
+	}
+	return ""
+}
+
 type Device struct {
 	ID              []byte // max:32
 	MaxLocalVersion int64
@@ -45,8 +45,10 @@ const (
 	FlagNoPermBits                  = 1 << 15
 	FlagSymlink                     = 1 << 16
 	FlagSymlinkMissingTarget        = 1 << 17
+	FlagHashAlgorithmMask           = 0xf << hashAlgorithmShift
+	FlagInvalidReasonMask           = 0xf << invalidReasonShift
 
-	FlagsAll = (1 << 18) - 1
+	FlagsAll = (1 << 26) - 1
 
 	SymlinkTypeMask = FlagDirectory | FlagSymlinkMissingTarget
 )
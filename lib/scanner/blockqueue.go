@@ -7,25 +7,40 @@
 package scanner
 
 import (
+	"io"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
+	"github.com/juju/ratelimit"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/osutil"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/sync"
 )
 
+// Files at least this large have their hashing progress checkpointed to the
+// database every hashCheckpointInterval bytes, so that it isn't lost on
+// restart.
+var (
+	hashCheckpointMinSize  int64 = 1 << 30
+	hashCheckpointInterval int64 = 128 << 20
+)
+
 // The parallell hasher reads FileInfo structures from the inbox, hashes the
 // file to populate the Blocks element and sends it to the outbox. A number of
 // workers are used in parallel. The outbox will become closed when the inbox
-// is closed and all items handled.
+// is closed and all items handled. Reads are rate limited by all of the given
+// buckets, which may be shared with other hashers. Progress on large files is
+// checkpointed to the given repository, if not nil.
 
-func newParallelHasher(dir string, blockSize, workers int, outbox, inbox chan protocol.FileInfo, counter *int64, done chan struct{}) {
+func newParallelHasher(dir string, blockSize int, algo protocol.HashAlgorithm, workers int, outbox, inbox chan protocol.FileInfo, counter *int64, done chan struct{}, limits []*ratelimit.Bucket, checkpoints *db.HashCheckpointRepo) {
 	wg := sync.NewWaitGroup()
 	wg.Add(workers)
 
 	for i := 0; i < workers; i++ {
 		go func() {
-			hashFiles(dir, blockSize, outbox, inbox, counter)
+			hashFiles(dir, blockSize, algo, outbox, inbox, counter, limits, checkpoints)
 			wg.Done()
 		}()
 	}
@@ -39,7 +54,11 @@ func newParallelHasher(dir string, blockSize, workers int, outbox, inbox chan pr
 	}()
 }
 
-func HashFile(path string, blockSize int, sizeHint int64, counter *int64) ([]protocol.BlockInfo, error) {
+func HashFile(path string, blockSize int, algo protocol.HashAlgorithm, sizeHint int64, counter *int64) ([]protocol.BlockInfo, error) {
+	return hashFile(path, blockSize, algo, sizeHint, counter, nil)
+}
+
+func hashFile(path string, blockSize int, algo protocol.HashAlgorithm, sizeHint int64, counter *int64, limits []*ratelimit.Bucket) ([]protocol.BlockInfo, error) {
 	fd, err := os.Open(path)
 	if err != nil {
 		if debug {
@@ -60,16 +79,27 @@ func HashFile(path string, blockSize int, sizeHint int64, counter *int64) ([]pro
 		sizeHint = fi.Size()
 	}
 
-	return Blocks(fd, blockSize, sizeHint, counter)
+	var r io.Reader = fd
+	if len(limits) > 0 {
+		r = &limitedReader{reader: fd, buckets: limits}
+	}
+
+	return Blocks(r, blockSize, algo, sizeHint, counter)
 }
 
-func hashFiles(dir string, blockSize int, outbox, inbox chan protocol.FileInfo, counter *int64) {
+func hashFiles(dir string, blockSize int, algo protocol.HashAlgorithm, outbox, inbox chan protocol.FileInfo, counter *int64, limits []*ratelimit.Bucket, checkpoints *db.HashCheckpointRepo) {
 	for f := range inbox {
 		if f.IsDirectory() || f.IsDeleted() {
 			panic("Bug. Asked to hash a directory or a deleted file.")
 		}
 
-		blocks, err := HashFile(filepath.Join(dir, f.Name), blockSize, f.CachedSize, counter)
+		var blocks []protocol.BlockInfo
+		var err error
+		if checkpoints != nil && f.CachedSize >= hashCheckpointMinSize {
+			blocks, err = hashFileResumable(dir, f.Name, blockSize, algo, counter, limits, checkpoints)
+		} else {
+			blocks, err = hashFile(filepath.Join(dir, f.Name), blockSize, algo, f.CachedSize, counter, limits)
+		}
 		if err != nil {
 			if debug {
 				l.Debugln("hash error:", f.Name, err)
@@ -78,6 +108,85 @@ func hashFiles(dir string, blockSize int, outbox, inbox chan protocol.FileInfo,
 		}
 
 		f.Blocks = blocks
+		f.Flags = f.Flags&^protocol.FlagHashAlgorithmMask | algo.Flags()
 		outbox <- f
 	}
 }
+
+// hashFileResumable hashes the file like hashFile, but continues from the
+// stored checkpoint if there is one and the file is unchanged since, and
+// stores checkpoints as it goes.
+func hashFileResumable(dir, name string, blockSize int, algo protocol.HashAlgorithm, counter *int64, limits []*ratelimit.Bucket, checkpoints *db.HashCheckpointRepo) ([]protocol.BlockInfo, error) {
+	fd, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		if debug {
+			l.Debugln("open:", err)
+		}
+		return []protocol.BlockInfo{}, err
+	}
+	defer fd.Close()
+
+	fi, err := fd.Stat()
+	if err != nil {
+		if debug {
+			l.Debugln("stat:", err)
+		}
+		return []protocol.BlockInfo{}, err
+	}
+
+	inode, _ := osutil.InodeOf(fi)
+	cur := db.HashCheckpoint{
+		Size:      fi.Size(),
+		Modified:  fi.ModTime().UnixNano(),
+		Inode:     inode,
+		Algorithm: algo,
+		BlockSize: int32(blockSize),
+	}
+
+	var blocks []protocol.BlockInfo
+	if cp, ok := checkpoints.Get(name); ok {
+		offset := int64(len(cp.Blocks)) * int64(blockSize)
+		if cp.Size == cur.Size && cp.Modified == cur.Modified && cp.Inode == cur.Inode &&
+			cp.Algorithm == cur.Algorithm && cp.BlockSize == cur.BlockSize && offset <= cur.Size {
+			if _, err := fd.Seek(offset, os.SEEK_SET); err == nil {
+				l.Infof("Resuming hashing of %q at %d of %d bytes", name, offset, cur.Size)
+				blocks = cp.Blocks
+				if counter != nil {
+					atomic.AddInt64(counter, offset)
+				}
+			}
+		} else if debug {
+			l.Debugln("discarding stale hash checkpoint for", name)
+		}
+	}
+
+	var r io.Reader = fd
+	if len(limits) > 0 {
+		r = &limitedReader{reader: fd, buckets: limits}
+	}
+
+	blocks, err = blocksFrom(r, blockSize, algo, cur.Size, counter, blocks, hashCheckpointInterval, func(bs []protocol.BlockInfo) {
+		cur.Blocks = bs
+		checkpoints.Put(name, cur)
+	})
+
+	// The checkpoint has served its purpose once we are done, and it's
+	// useless if hashing failed as the file has changed or gone away.
+	checkpoints.Delete(name)
+
+	return blocks, err
+}
+
+// A limitedReader waits on each of the buckets for the amount of data read.
+type limitedReader struct {
+	reader  io.Reader
+	buckets []*ratelimit.Bucket
+}
+
+func (r *limitedReader) Read(buf []byte) (int, error) {
+	n, err := r.reader.Read(buf)
+	for _, b := range r.buckets {
+		b.Wait(int64(n))
+	}
+	return n, err
+}
@@ -8,7 +8,6 @@ package scanner
 
 import (
 	"bytes"
-	"crypto/sha256"
 	"fmt"
 	"io"
 	"sync/atomic"
@@ -18,38 +17,102 @@ import (
 
 var SHA256OfNothing = []uint8{0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24, 0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55}
 
-// Blocks returns the blockwise hash of the reader.
-func Blocks(r io.Reader, blocksize int, sizehint int64, counter *int64) ([]protocol.BlockInfo, error) {
-	var blocks []protocol.BlockInfo
-	if sizehint > 0 {
+// zeroBlock is a full size block of zeros, and zeroBlockHashes the hash of
+// it for each algorithm. Blocks with these hashes are sparse regions that
+// need neither be read, written nor transferred.
+var (
+	zeroBlock       = make([]byte, protocol.BlockSize)
+	zeroBlockHashes = make(map[protocol.HashAlgorithm][]byte)
+)
+
+func init() {
+	for _, algo := range protocol.HashAlgorithms {
+		hf := algo.New()
+		hf.Write(zeroBlock)
+		zeroBlockHashes[algo] = hf.Sum(nil)
+	}
+}
+
+// IsZeroBlock returns true if the block is a full size block of all zeros,
+// hashed with the given algorithm.
+func IsZeroBlock(block protocol.BlockInfo, algo protocol.HashAlgorithm) bool {
+	return block.Size == protocol.BlockSize && bytes.Equal(block.Hash, zeroBlockHashes[algo])
+}
+
+// IsZeroBlockHash returns true if the hash is that of a block of all zeros
+// of the given size, for any hash algorithm.
+func IsZeroBlockHash(hash []byte, size int) bool {
+	if size != protocol.BlockSize {
+		return false
+	}
+	for _, zh := range zeroBlockHashes {
+		if bytes.Equal(hash, zh) {
+			return true
+		}
+	}
+	return false
+}
+
+// Blocks returns the blockwise hash of the reader, computed using the given
+// hash algorithm.
+func Blocks(r io.Reader, blocksize int, algo protocol.HashAlgorithm, sizehint int64, counter *int64) ([]protocol.BlockInfo, error) {
+	return blocksFrom(r, blocksize, algo, sizehint, counter, nil, 0, nil)
+}
+
+// blocksFrom continues hashing the reader after the given, already hashed,
+// full size blocks. The reader must be positioned at the end of those. If
+// checkpoint is not nil it is called with the blocks hashed so far every
+// checkpointEvery bytes.
+func blocksFrom(r io.Reader, blocksize int, algo protocol.HashAlgorithm, sizehint int64, counter *int64, blocks []protocol.BlockInfo, checkpointEvery int64, checkpoint func([]protocol.BlockInfo)) ([]protocol.BlockInfo, error) {
+	if sizehint > 0 && blocks == nil {
 		blocks = make([]protocol.BlockInfo, 0, int(sizehint/int64(blocksize)))
 	}
 	var offset int64
-	hf := sha256.New()
+	for _, b := range blocks {
+		offset += int64(b.Size)
+	}
+	lastCheckpoint := offset
+	hf := algo.New()
+	buf := make([]byte, blocksize)
 	for {
-		lr := &io.LimitedReader{R: r, N: int64(blocksize)}
-		n, err := io.Copy(hf, lr)
-		if err != nil {
-			return nil, err
-		}
-
-		if n == 0 {
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF {
 			break
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
 		}
 
 		if counter != nil {
 			atomic.AddInt64(counter, int64(n))
 		}
 
+		var hash []byte
+		if n == protocol.BlockSize && bytes.Equal(buf, zeroBlock) {
+			// Sparse regions are common in disk images and the like;
+			// there is no need to hash them over and over again.
+			hash = zeroBlockHashes[algo]
+		} else {
+			hf.Write(buf[:n])
+			hash = hf.Sum(nil)
+			hf.Reset()
+		}
+
 		b := protocol.BlockInfo{
 			Size:   int32(n),
 			Offset: offset,
-			Hash:   hf.Sum(nil),
+			Hash:   hash,
 		}
 		blocks = append(blocks, b)
 		offset += int64(n)
 
-		hf.Reset()
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if checkpoint != nil && n == blocksize && offset-lastCheckpoint >= checkpointEvery {
+			checkpoint(blocks)
+			lastCheckpoint = offset
+		}
 	}
 
 	if len(blocks) == 0 {
@@ -57,7 +120,7 @@ func Blocks(r io.Reader, blocksize int, sizehint int64, counter *int64) ([]proto
 		blocks = append(blocks, protocol.BlockInfo{
 			Offset: 0,
 			Size:   0,
-			Hash:   SHA256OfNothing,
+			Hash:   hf.Sum(nil),
 		})
 	}
 
@@ -99,8 +162,8 @@ func BlockDiff(src, tgt []protocol.BlockInfo) (have, need []protocol.BlockInfo)
 
 // Verify returns nil or an error describing the mismatch between the block
 // list and actual reader contents
-func Verify(r io.Reader, blocksize int, blocks []protocol.BlockInfo) error {
-	hf := sha256.New()
+func Verify(r io.Reader, blocksize int, algo protocol.HashAlgorithm, blocks []protocol.BlockInfo) error {
+	hf := algo.New()
 	for i, block := range blocks {
 		lr := &io.LimitedReader{R: r, N: int64(blocksize)}
 		_, err := io.Copy(hf, lr)
@@ -126,11 +189,13 @@ func Verify(r io.Reader, blocksize int, blocks []protocol.BlockInfo) error {
 	return nil
 }
 
-func VerifyBuffer(buf []byte, block protocol.BlockInfo) ([]byte, error) {
+// VerifyBuffer returns the hash of the buffer computed using the given
+// algorithm, and an error if it doesn't match the block.
+func VerifyBuffer(buf []byte, block protocol.BlockInfo, algo protocol.HashAlgorithm) ([]byte, error) {
 	if len(buf) != int(block.Size) {
 		return nil, fmt.Errorf("length mismatch %d != %d", len(buf), block.Size)
 	}
-	hf := sha256.New()
+	hf := algo.New()
 	_, err := hf.Write(buf)
 	if err != nil {
 		return nil, err
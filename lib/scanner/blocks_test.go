@@ -9,8 +9,15 @@ package scanner
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/juju/ratelimit"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/osutil"
 	"github.com/syncthing/syncthing/lib/protocol"
 )
 
@@ -51,7 +58,7 @@ var blocksTestData = []struct {
 func TestBlocks(t *testing.T) {
 	for _, test := range blocksTestData {
 		buf := bytes.NewBuffer(test.data)
-		blocks, err := Blocks(buf, test.blocksize, 0, nil)
+		blocks, err := Blocks(buf, test.blocksize, protocol.SHA256, 0, nil)
 
 		if err != nil {
 			t.Fatal(err)
@@ -83,6 +90,45 @@ func TestBlocks(t *testing.T) {
 	}
 }
 
+func TestBlocksBLAKE2b(t *testing.T) {
+	tests := []struct {
+		data      string
+		blocksize int
+		hash      []string
+	}{
+		{"", 1024, []string{
+			"0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8"}},
+		{"contents", 1024, []string{
+			"f2fb66fbd40e9aafe561d41a341a751575e6483847a5a1dd8dfa7e46c8965282"}},
+		{"contents", 3, []string{
+			"a30ea63f32e13e3ab3320ae5ffac34213ab43c934a1501b7785e4318650e7c34",
+			"1359907074e9befee8a2f9d651ec18d884e51f4eaaaa85febc3f85395105d3c4",
+			"64c189d339335d8a5bb67512d59905babc6bfdce1b05c949ce5e646502055869"},
+		},
+	}
+
+	for _, test := range tests {
+		blocks, err := Blocks(bytes.NewBufferString(test.data), test.blocksize, protocol.BLAKE2b, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l := len(blocks); l != len(test.hash) {
+			t.Fatalf("Incorrect number of blocks %d != %d", l, len(test.hash))
+		}
+		for i := range blocks {
+			if h := fmt.Sprintf("%x", blocks[i].Hash); h != test.hash[i] {
+				t.Errorf("Incorrect block hash %q != %q", h, test.hash[i])
+			}
+			if _, err := VerifyBuffer([]byte(test.data)[blocks[i].Offset:blocks[i].Offset+int64(blocks[i].Size)], blocks[i], protocol.BLAKE2b); err != nil {
+				t.Error(err)
+			}
+			if _, err := VerifyBuffer([]byte(test.data)[blocks[i].Offset:blocks[i].Offset+int64(blocks[i].Size)], blocks[i], protocol.SHA256); err == nil {
+				t.Error("Unexpected nil error verifying with the wrong algorithm")
+			}
+		}
+	}
+}
+
 var diffTestData = []struct {
 	a string
 	b string
@@ -105,8 +151,8 @@ var diffTestData = []struct {
 
 func TestDiff(t *testing.T) {
 	for i, test := range diffTestData {
-		a, _ := Blocks(bytes.NewBufferString(test.a), test.s, 0, nil)
-		b, _ := Blocks(bytes.NewBufferString(test.b), test.s, 0, nil)
+		a, _ := Blocks(bytes.NewBufferString(test.a), test.s, protocol.SHA256, 0, nil)
+		b, _ := Blocks(bytes.NewBufferString(test.b), test.s, protocol.SHA256, 0, nil)
 		_, d := BlockDiff(a, b)
 		if len(d) != len(test.d) {
 			t.Fatalf("Incorrect length for diff %d; %d != %d", i, len(d), len(test.d))
@@ -122,3 +168,136 @@ func TestDiff(t *testing.T) {
 		}
 	}
 }
+
+func TestLimitedReader(t *testing.T) {
+	data := make([]byte, 256*1024)
+
+	// Allows no burst, and 1 MB/s after that, so reading the data takes at
+	// least a quarter of a second through either bucket.
+	fast := ratelimit.NewBucketWithRate(2*1000*1000, 1)
+	slow := ratelimit.NewBucketWithRate(1000*1000, 1)
+
+	t0 := time.Now()
+	blocks, err := Blocks(&limitedReader{reader: bytes.NewReader(data), buckets: []*ratelimit.Bucket{fast, slow}}, 128*1024, protocol.SHA256, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(t0); d < 200*time.Millisecond {
+		t.Errorf("Hashing was not rate limited; took %v", d)
+	}
+	if len(blocks) != 2 {
+		t.Errorf("Incorrect number of blocks %d != 2", len(blocks))
+	}
+}
+
+func TestHashFileResumable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const blockSize = 1024
+	data := make([]byte, 10*blockSize+100)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	path := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldInterval := hashCheckpointInterval
+	hashCheckpointInterval = 2 * blockSize
+	defer func() { hashCheckpointInterval = oldInterval }()
+
+	expected, err := Blocks(bytes.NewReader(data), blockSize, protocol.SHA256, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := db.NewHashCheckpointRepo(db.OpenMemory(), "default")
+	cp := db.HashCheckpoint{
+		Size:      info.Size(),
+		Modified:  info.ModTime().UnixNano(),
+		Algorithm: protocol.SHA256,
+		BlockSize: blockSize,
+	}
+	cp.Inode, _ = osutil.InodeOf(info)
+
+	// Stick a bogus hash in the first block to make sure the checkpointed
+	// blocks are used as is.
+	cp.Blocks = append([]protocol.BlockInfo(nil), expected[:4]...)
+	cp.Blocks[0].Hash = bytes.Repeat([]byte{0xff}, 32)
+	repo.Put("file", cp)
+
+	var counter int64
+	blocks, err := hashFileResumable(dir, "file", blockSize, protocol.SHA256, &counter, nil, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter != int64(len(data)) {
+		t.Errorf("counter %d != %d", counter, len(data))
+	}
+	if len(blocks) != len(expected) {
+		t.Fatalf("got %d blocks, expected %d", len(blocks), len(expected))
+	}
+	if !bytes.Equal(blocks[0].Hash, cp.Blocks[0].Hash) {
+		t.Error("checkpointed blocks were not used")
+	}
+	for i := 1; i < len(expected); i++ {
+		if blocks[i].Offset != expected[i].Offset || blocks[i].Size != expected[i].Size ||
+			!bytes.Equal(blocks[i].Hash, expected[i].Hash) {
+			t.Errorf("block %d mismatch: %v != %v", i, blocks[i], expected[i])
+		}
+	}
+	if _, ok := repo.Get("file"); ok {
+		t.Error("checkpoint not removed after hashing")
+	}
+
+	// A checkpoint for a file that has since been modified is ignored.
+	cp.Modified--
+	repo.Put("file", cp)
+	blocks, err = hashFileResumable(dir, "file", blockSize, protocol.SHA256, nil, nil, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range expected {
+		if !bytes.Equal(blocks[i].Hash, expected[i].Hash) {
+			t.Errorf("block %d hash mismatch after full rehash", i)
+		}
+	}
+}
+
+func TestBlocksZero(t *testing.T) {
+	data := make([]byte, 2*protocol.BlockSize+5)
+
+	for _, algo := range protocol.HashAlgorithms {
+		blocks, err := Blocks(bytes.NewReader(data), protocol.BlockSize, algo, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blocks) != 3 {
+			t.Fatalf("%v: incorrect number of blocks %d != 3", algo, len(blocks))
+		}
+
+		hf := algo.New()
+		hf.Write(data[:protocol.BlockSize])
+		if !bytes.Equal(blocks[0].Hash, hf.Sum(nil)) {
+			t.Errorf("%v: incorrect hash for zero block", algo)
+		}
+
+		for i, zero := range []bool{true, true, false} {
+			if IsZeroBlock(blocks[i], algo) != zero {
+				t.Errorf("%v: IsZeroBlock(%d) != %v", algo, i, zero)
+			}
+			if IsZeroBlockHash(blocks[i].Hash, int(blocks[i].Size)) != zero {
+				t.Errorf("%v: IsZeroBlockHash(%d) != %v", algo, i, zero)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The platforms understood by CheckName. Names are as in runtime.GOOS.
+var NamePlatforms = []string{"windows", "darwin", "linux"}
+
+const (
+	maxComponentLen   = 255  // bytes, or UTF-16 units on Windows and Mac
+	maxPathLenWindows = 259  // MAX_PATH, less the terminating NUL
+	maxPathLenLinux   = 4095 // PATH_MAX, less the terminating NUL
+)
+
+var windowsReservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// CheckName returns the reason the file name, relative to the folder root,
+// would be unusable on any of the given platforms, or InvalidReasonNone.
+// Unknown platforms are ignored. Path lengths are checked without the folder
+// root, which is unknown on the other side, so only names that can never
+// work are caught.
+func CheckName(name string, platforms []string) protocol.InvalidReason {
+	for _, platform := range platforms {
+		var reason protocol.InvalidReason
+		switch platform {
+		case "windows":
+			reason = checkNameWindows(name)
+		case "darwin":
+			reason = checkNameDarwin(name)
+		case "linux":
+			reason = checkNameLinux(name)
+		}
+		if reason != protocol.InvalidReasonNone {
+			return reason
+		}
+	}
+	return protocol.InvalidReasonNone
+}
+
+func checkNameWindows(name string) protocol.InvalidReason {
+	if utf16Len(name) > maxPathLenWindows {
+		return protocol.InvalidReasonTooLong
+	}
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		if strings.IndexFunc(part, func(r rune) bool {
+			return r < 32 || strings.ContainsRune(`<>:"/\|?*`, r)
+		}) >= 0 {
+			return protocol.InvalidReasonCharacters
+		}
+		base := part
+		if i := strings.IndexByte(base, '.'); i >= 0 {
+			base = base[:i]
+		}
+		if _, ok := windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))]; ok {
+			return protocol.InvalidReasonReservedName
+		}
+		if strings.HasSuffix(part, " ") || strings.HasSuffix(part, ".") {
+			return protocol.InvalidReasonTrailingChar
+		}
+		if utf16Len(part) > maxComponentLen {
+			return protocol.InvalidReasonTooLong
+		}
+	}
+	return protocol.InvalidReasonNone
+}
+
+func checkNameDarwin(name string) protocol.InvalidReason {
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		if strings.ContainsRune(part, ':') {
+			return protocol.InvalidReasonCharacters
+		}
+		if utf16Len(part) > maxComponentLen {
+			return protocol.InvalidReasonTooLong
+		}
+	}
+	return protocol.InvalidReasonNone
+}
+
+func checkNameLinux(name string) protocol.InvalidReason {
+	if len(name) > maxPathLenLinux {
+		return protocol.InvalidReasonTooLong
+	}
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		if len(part) > maxComponentLen {
+			return protocol.InvalidReasonTooLong
+		}
+	}
+	return protocol.InvalidReasonNone
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
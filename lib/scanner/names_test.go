@@ -0,0 +1,51 @@
+// Copyright (C) 2015 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestCheckName(t *testing.T) {
+	long := strings.Repeat("a", 256)
+
+	cases := []struct {
+		name      string
+		platforms []string
+		reason    protocol.InvalidReason
+	}{
+		{"normal.txt", NamePlatforms, protocol.InvalidReasonNone},
+		{filepath.Join("dir", "normal.txt"), NamePlatforms, protocol.InvalidReasonNone},
+		{"what?.txt", []string{"linux", "darwin"}, protocol.InvalidReasonNone},
+		{"what?.txt", []string{"windows"}, protocol.InvalidReasonCharacters},
+		{filepath.Join("a:b", "file"), []string{"windows"}, protocol.InvalidReasonCharacters},
+		{"a:b", []string{"darwin"}, protocol.InvalidReasonCharacters},
+		{"tab\there", []string{"windows"}, protocol.InvalidReasonCharacters},
+		{"CON", []string{"windows"}, protocol.InvalidReasonReservedName},
+		{"con.txt", []string{"windows"}, protocol.InvalidReasonReservedName},
+		{filepath.Join("lpt1", "file"), []string{"windows"}, protocol.InvalidReasonReservedName},
+		{"CONSOLE", []string{"windows"}, protocol.InvalidReasonNone},
+		{"trailing.", []string{"windows"}, protocol.InvalidReasonTrailingChar},
+		{"trailing ", []string{"windows"}, protocol.InvalidReasonTrailingChar},
+		{"trailing ", []string{"linux"}, protocol.InvalidReasonNone},
+		{long, []string{"linux"}, protocol.InvalidReasonTooLong},
+		{long, []string{"darwin"}, protocol.InvalidReasonTooLong},
+		{filepath.Join(long[:200], long[:200]), []string{"windows"}, protocol.InvalidReasonTooLong},
+		{filepath.Join(long[:200], long[:200]), []string{"linux"}, protocol.InvalidReasonNone},
+		{"CON", []string{"plan9"}, protocol.InvalidReasonNone},
+	}
+
+	for _, tc := range cases {
+		if reason := CheckName(tc.name, tc.platforms); reason != tc.reason {
+			t.Errorf("CheckName(%q, %v) = %v, expected %v", tc.name, tc.platforms, reason, tc.reason)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/juju/ratelimit"
 	"github.com/syncthing/syncthing/lib/db"
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/osutil"
@@ -48,6 +49,9 @@ type Walker struct {
 	Subs []string
 	// BlockSize controls the size of the block used when hashing.
 	BlockSize int
+	// HashAlgorithm is used to hash new and changed files. Unchanged files
+	// keep the algorithm they were hashed with.
+	HashAlgorithm protocol.HashAlgorithm
 	// If Matcher is not nil, it is used to identify files to ignore which were specified by the user.
 	Matcher IgnoreMatcher
 	// If TempNamer is not nil, it is used to ignore temporary files when walking.
@@ -58,6 +62,13 @@ type Walker struct {
 	CurrentFiler CurrentFiler
 	// If MtimeRepo is not nil, it is used to provide mtimes on systems that don't support setting arbirtary mtimes.
 	MtimeRepo *db.VirtualMtimeRepo
+	// If HashCheckpoints is not nil, hashing progress on large files is
+	// stored there so that it can be resumed after a restart.
+	HashCheckpoints *db.HashCheckpointRepo
+	// If InodeRepo is not nil, it is used to recognize files that have been
+	// moved within the folder, which then keep their blocks instead of
+	// being rehashed. Requires CurrentFiler.
+	InodeRepo *db.InodeRepo
 	// If IgnorePerms is true, changes to permission bits will not be
 	// detected. Scanned files will get zero permission bits and the
 	// NoPermissionBits flag set.
@@ -65,8 +76,23 @@ type Walker struct {
 	// When AutoNormalize is set, file names that are in UTF8 but incorrect
 	// normalization form will be corrected.
 	AutoNormalize bool
+	// If TargetPlatforms is not empty, file names unusable on any of them
+	// (see CheckName) are marked invalid with the reason, instead of being
+	// hashed.
+	TargetPlatforms []string
+	// If MaxFileSize is positive, larger files are marked invalid instead
+	// of being hashed.
+	MaxFileSize int64
+	// If MinFileAge is positive, files modified more recently than this are
+	// marked invalid instead of being hashed, as they may still be in the
+	// process of being written.
+	MinFileAge time.Duration
 	// Number of routines to use for hashing
 	Hashers int
+	// If RateLimits is not empty, reads while hashing are limited by each of
+	// the buckets. Buckets may be shared with other walkers to enforce a
+	// common limit.
+	RateLimits []*ratelimit.Bucket
 	// Our vector clock id
 	ShortID uint64
 	// Optional progress tick interval which defines how often FolderScanProgress
@@ -87,8 +113,8 @@ type CurrentFiler interface {
 }
 
 type IgnoreMatcher interface {
-	// Match returns true if the file should be ignored.
-	Match(filename string) bool
+	// MatchFile returns true if the file should be ignored.
+	MatchFile(filename string, isDir bool) bool
 }
 
 // Walk returns the list of files found in the local folder by scanning the
@@ -117,13 +143,18 @@ func (w *Walker) Walk() (chan protocol.FileInfo, error) {
 				filepath.Walk(filepath.Join(w.Dir, sub), hashFiles)
 			}
 		}
+		if w.InodeRepo != nil && w.CurrentFiler != nil {
+			// Every file found has had its inode recorded, so the other
+			// entries within the scanned part of the folder are stale.
+			w.InodeRepo.Prune(w.scanned)
+		}
 		close(toHashChan)
 	}()
 
 	// We're not required to emit scan progress events, just kick off hashers,
 	// and feed inputs directly from the walker.
 	if w.ProgressTickIntervalS < 0 {
-		newParallelHasher(w.Dir, w.BlockSize, w.Hashers, finishedChan, toHashChan, nil, nil)
+		newParallelHasher(w.Dir, w.BlockSize, w.HashAlgorithm, w.Hashers, finishedChan, toHashChan, nil, nil, w.RateLimits, w.HashCheckpoints)
 		return finishedChan, nil
 	}
 
@@ -151,7 +182,7 @@ func (w *Walker) Walk() (chan protocol.FileInfo, error) {
 
 		realToHashChan := make(chan protocol.FileInfo)
 		done := make(chan struct{})
-		newParallelHasher(w.Dir, w.BlockSize, w.Hashers, finishedChan, realToHashChan, &progress, done)
+		newParallelHasher(w.Dir, w.BlockSize, w.HashAlgorithm, w.Hashers, finishedChan, realToHashChan, &progress, done, w.RateLimits, w.HashCheckpoints)
 
 		// A routine which actually emits the FolderScanProgress events
 		// every w.ProgressTicker ticks, until the hasher routines terminate.
@@ -240,7 +271,7 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 		}
 
 		if sn := filepath.Base(rn); sn == ".stignore" || sn == ".stfolder" ||
-			strings.HasPrefix(rn, ".stversions") || (w.Matcher != nil && w.Matcher.Match(rn)) {
+			strings.HasPrefix(rn, ".stversions") || (w.Matcher != nil && w.Matcher.MatchFile(rn, info.IsDir())) {
 			// An ignored file
 			if debug {
 				l.Debugln("ignored:", rn)
@@ -293,6 +324,20 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 			rn = normalizedRn
 		}
 
+		if len(w.TargetPlatforms) > 0 {
+			if reason := CheckName(rn, w.TargetPlatforms); reason != protocol.InvalidReasonNone {
+				w.invalidFile(rn, info, mtime, reason, dchan)
+				return skip
+			}
+		}
+
+		if info.Mode().IsRegular() {
+			if reason := w.checkAttributes(info, mtime, now); reason != protocol.InvalidReasonNone {
+				w.invalidFile(rn, info, mtime, reason, dchan)
+				return skip
+			}
+		}
+
 		var cf protocol.FileInfo
 		var ok bool
 
@@ -319,7 +364,18 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 				return skip
 			}
 
-			blocks, err := Blocks(strings.NewReader(target), w.BlockSize, 0, nil)
+			// Compare using the algorithm the current entry was hashed
+			// with, so that the hash algorithm setting doesn't by itself
+			// cause a change.
+			algo := w.HashAlgorithm
+			if w.CurrentFiler != nil {
+				cf, ok = w.CurrentFiler.CurrentFile(rn)
+				if ok && cf.IsSymlink() && cf.HashAlgorithm().IsKnown() {
+					algo = cf.HashAlgorithm()
+				}
+			}
+
+			blocks, err := Blocks(strings.NewReader(target), w.BlockSize, algo, 0, nil)
 			if err != nil {
 				if debug {
 					l.Debugln("hash link error:", p, err)
@@ -335,16 +391,27 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 				//  - it wasn't invalid
 				//  - the symlink type (file/dir) was the same
 				//  - the block list (i.e. hash of target) was the same
-				cf, ok = w.CurrentFiler.CurrentFile(rn)
 				if ok && !cf.IsDeleted() && cf.IsSymlink() && !cf.IsInvalid() && SymlinkTypeEqual(targetType, cf) && BlocksEqual(cf.Blocks, blocks) {
 					return skip
 				}
 			}
 
+			if algo != w.HashAlgorithm {
+				// Changed, so it gets the currently configured algorithm.
+				algo = w.HashAlgorithm
+				blocks, err = Blocks(strings.NewReader(target), w.BlockSize, algo, 0, nil)
+				if err != nil {
+					if debug {
+						l.Debugln("hash link error:", p, err)
+					}
+					return skip
+				}
+			}
+
 			f := protocol.FileInfo{
 				Name:     rn,
 				Version:  cf.Version.Update(w.ShortID),
-				Flags:    uint32(protocol.FlagSymlink | protocol.FlagNoPermBits | 0666 | SymlinkFlags(targetType)),
+				Flags:    uint32(protocol.FlagSymlink|protocol.FlagNoPermBits|0666|SymlinkFlags(targetType)) | algo.Flags(),
 				Modified: 0,
 				Blocks:   blocks,
 			}
@@ -399,6 +466,10 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 				curMode |= 0111
 			}
 
+			dev, devOk := osutil.DeviceOf(info)
+			ino, inoOk := osutil.InodeOf(info)
+			trackInode := w.InodeRepo != nil && w.CurrentFiler != nil && devOk && inoOk
+
 			if w.CurrentFiler != nil {
 				// A file is "unchanged", if it
 				//  - exists
@@ -413,6 +484,9 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 				permUnchanged := w.IgnorePerms || !cf.HasPermissionBits() || PermsEqual(cf.Flags, curMode)
 				if ok && permUnchanged && !cf.IsDeleted() && cf.Modified == mtime.Unix() && !cf.IsDirectory() &&
 					!cf.IsSymlink() && !cf.IsInvalid() && cf.Size() == info.Size() {
+					if trackInode {
+						w.InodeRepo.Record(dev, ino, rn)
+					}
 					return nil
 				}
 
@@ -433,6 +507,35 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 				Modified:   mtime.Unix(),
 				CachedSize: info.Size(),
 			}
+
+			if trackInode {
+				var old protocol.FileInfo
+				var moved bool
+				if !ok || cf.IsDeleted() {
+					old, moved = w.movedFrom(f, dev, ino)
+				}
+				w.InodeRepo.Record(dev, ino, rn)
+
+				if moved {
+					// Announce the deletion of the old name together with
+					// the new file carrying the same blocks, which lets
+					// other devices perform the move as a rename.
+					if debug {
+						l.Debugln("moved:", old.Name, "->", rn)
+					}
+					dchan <- protocol.FileInfo{
+						Name:     old.Name,
+						Flags:    old.Flags | protocol.FlagDeleted,
+						Modified: old.Modified,
+						Version:  old.Version.Update(w.ShortID),
+					}
+					f.Flags |= old.HashAlgorithm().Flags()
+					f.Blocks = old.Blocks
+					dchan <- f
+					return nil
+				}
+			}
+
 			if debug {
 				l.Debugln("to hash:", p, f)
 			}
@@ -443,6 +546,89 @@ func (w *Walker) walkAndHashFiles(fchan, dchan chan protocol.FileInfo) filepath.
 	}
 }
 
+// scanned returns true if the file name is within the part of the folder
+// being scanned.
+func (w *Walker) scanned(name string) bool {
+	if len(w.Subs) == 0 {
+		return true
+	}
+	for _, sub := range w.Subs {
+		sub = filepath.Clean(sub)
+		if name == sub || strings.HasPrefix(name, sub+string(os.PathSeparator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAttributes returns the reason the file should not be synced given its
+// size and modification time, or InvalidReasonNone.
+func (w *Walker) checkAttributes(info os.FileInfo, mtime, now time.Time) protocol.InvalidReason {
+	if w.MaxFileSize > 0 && info.Size() > w.MaxFileSize {
+		return protocol.InvalidReasonTooLarge
+	}
+	if w.MinFileAge > 0 && now.Sub(mtime) < w.MinFileAge {
+		return protocol.InvalidReasonTooRecent
+	}
+	return protocol.InvalidReasonNone
+}
+
+// invalidFile announces the file as invalid for the given reason, unless it
+// already is.
+func (w *Walker) invalidFile(rn string, info os.FileInfo, mtime time.Time, reason protocol.InvalidReason, dchan chan protocol.FileInfo) {
+	var cf protocol.FileInfo
+	if w.CurrentFiler != nil {
+		var ok bool
+		cf, ok = w.CurrentFiler.CurrentFile(rn)
+		if ok && !cf.IsDeleted() && cf.IsInvalid() && cf.InvalidReason() == reason {
+			return
+		}
+	}
+
+	l.Infof("Not syncing %q in folder %q: %v", rn, w.Folder, reason)
+
+	flags := protocol.FlagInvalid | reason.Flags()
+	if info.Mode()&os.ModeSymlink != 0 {
+		flags |= protocol.FlagSymlink
+	} else if info.IsDir() {
+		flags |= protocol.FlagDirectory
+	}
+	f := protocol.FileInfo{
+		Name:     rn,
+		Version:  cf.Version.Update(w.ShortID),
+		Flags:    flags | protocol.FlagNoPermBits,
+		Modified: mtime.Unix(),
+	}
+	if debug {
+		l.Debugln("invalid:", rn, f)
+	}
+	dchan <- f
+}
+
+// movedFrom returns the current index entry of the file that f, a file not
+// previously in the index, has been moved from. That is the file last seen
+// with the same device and inode numbers, if it no longer exists under its
+// old name and had the same size and modification time as f has now.
+func (w *Walker) movedFrom(f protocol.FileInfo, dev, ino uint64) (protocol.FileInfo, bool) {
+	oldName, ok := w.InodeRepo.Lookup(dev, ino)
+	if !ok || oldName == f.Name {
+		return protocol.FileInfo{}, false
+	}
+
+	old, ok := w.CurrentFiler.CurrentFile(oldName)
+	if !ok || old.IsDeleted() || old.IsDirectory() || old.IsSymlink() || old.IsInvalid() ||
+		!old.HashAlgorithm().IsKnown() || old.Modified != f.Modified || old.Size() != f.CachedSize {
+		return protocol.FileInfo{}, false
+	}
+
+	if _, err := osutil.Lstat(filepath.Join(w.Dir, oldName)); err == nil {
+		// Still there, so this is a hard link or a reused inode.
+		return protocol.FileInfo{}, false
+	}
+
+	return old, true
+}
+
 func checkDir(dir string) error {
 	if info, err := osutil.Lstat(dir); err != nil {
 		return err
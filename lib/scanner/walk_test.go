@@ -9,6 +9,7 @@ package scanner
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -16,7 +17,9 @@ import (
 	rdebug "runtime/debug"
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/syncthing/syncthing/lib/db"
 	"github.com/syncthing/syncthing/lib/ignore"
 	"github.com/syncthing/syncthing/lib/osutil"
 	"github.com/syncthing/syncthing/lib/protocol"
@@ -90,6 +93,285 @@ func TestWalkSub(t *testing.T) {
 	}
 }
 
+func TestWalkHashAlgorithm(t *testing.T) {
+	w := Walker{
+		Dir:           "testdata",
+		Subs:          []string{"dir2"},
+		BlockSize:     128 * 1024,
+		HashAlgorithm: protocol.BLAKE2b,
+		Hashers:       2,
+	}
+	fchan, err := w.Walk()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for f := range fchan {
+		if f.IsDirectory() {
+			continue
+		}
+		if algo := f.HashAlgorithm(); algo != protocol.BLAKE2b {
+			t.Errorf("Incorrect hash algorithm %v for %q", algo, f.Name)
+		}
+		fd, err := os.Open(filepath.Join("testdata", f.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Verify(fd, w.BlockSize, protocol.BLAKE2b, f.Blocks)
+		fd.Close()
+		if err != nil {
+			t.Errorf("%q: %v", f.Name, err)
+		}
+	}
+}
+
+type fakeCurrentFiler map[string]protocol.FileInfo
+
+func (fcf fakeCurrentFiler) CurrentFile(name string) (protocol.FileInfo, bool) {
+	f, ok := fcf[name]
+	return f, ok
+}
+
+func TestWalkMovedFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("inode numbers are not available on Windows")
+	}
+
+	dir, err := ioutil.TempDir("", "moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "old"), []byte("some data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := db.NewInodeRepo(db.OpenMemory(), "default")
+	cfiler := make(fakeCurrentFiler)
+	w := Walker{
+		Dir:          dir,
+		BlockSize:    128 * 1024,
+		Hashers:      2,
+		CurrentFiler: cfiler,
+		InodeRepo:    repo,
+	}
+
+	files := walkWith(t, &w)
+	if len(files) != 1 || files[0].Name != "old" {
+		t.Fatalf("unexpected initial scan result %v", files)
+	}
+
+	// Use bogus blocks in the index, to tell whether the moved file gets
+	// rehashed or not.
+	old := files[0]
+	old.Blocks = []protocol.BlockInfo{{Size: 9, Hash: bytes.Repeat([]byte{0x42}, 32)}}
+	cfiler["old"] = old
+
+	if err := os.Rename(filepath.Join(dir, "old"), filepath.Join(dir, "new")); err != nil {
+		t.Fatal(err)
+	}
+
+	files = walkWith(t, &w)
+	if len(files) != 2 {
+		t.Fatalf("expected deletion and new file, got %v", files)
+	}
+	if files[0].Name != "new" || files[0].IsDeleted() || !BlocksEqual(files[0].Blocks, old.Blocks) {
+		t.Errorf("moved file did not inherit blocks: %v", files[0])
+	}
+	if files[1].Name != "old" || !files[1].IsDeleted() {
+		t.Errorf("old file not deleted: %v", files[1])
+	}
+
+	// A new file with a recorded inode, while the old file is still around,
+	// is not a move.
+	if err := os.Link(filepath.Join(dir, "new"), filepath.Join(dir, "link")); err != nil {
+		t.Skip("hard links not supported:", err)
+	}
+	cfiler["new"] = files[0]
+	files = walkWith(t, &w)
+	if len(files) != 1 || files[0].Name != "link" || BlocksEqual(files[0].Blocks, old.Blocks) {
+		t.Errorf("hard link treated as a move: %v", files)
+	}
+}
+
+func TestWalkPrunesInodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inodes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", filepath.Join("sub", "c")} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inodes := make(map[string][2]uint64)
+	for _, name := range []string{"a", "b"} {
+		info, err := os.Lstat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dev, devOk := osutil.DeviceOf(info)
+		ino, inoOk := osutil.InodeOf(info)
+		if !devOk || !inoOk {
+			t.Skip("inode numbers not supported")
+		}
+		inodes[name] = [2]uint64{dev, ino}
+	}
+
+	repo := db.NewInodeRepo(db.OpenMemory(), "default")
+	w := Walker{
+		Dir:          dir,
+		BlockSize:    128 * 1024,
+		Hashers:      2,
+		CurrentFiler: make(fakeCurrentFiler),
+		InodeRepo:    repo,
+	}
+	walkWith(t, &w)
+
+	os.Remove(filepath.Join(dir, "a"))
+	os.Remove(filepath.Join(dir, "b"))
+
+	// A scan of another subdirectory keeps the entries of files outside it.
+	w.Subs = []string{"sub"}
+	walkWith(t, &w)
+	if _, ok := repo.Lookup(inodes["a"][0], inodes["a"][1]); !ok {
+		t.Error("entry outside the scanned subdirectory should be kept")
+	}
+
+	w.Subs = nil
+	walkWith(t, &w)
+	for name, inode := range inodes {
+		if _, ok := repo.Lookup(inode[0], inode[1]); ok {
+			t.Errorf("entry of deleted file %q should be pruned", name)
+		}
+	}
+}
+
+func walkWith(t *testing.T, w *Walker) []protocol.FileInfo {
+	fchan, err := w.Walk()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tmp []protocol.FileInfo
+	for f := range fchan {
+		tmp = append(tmp, f)
+	}
+	sort.Sort(fileList(tmp))
+
+	return tmp
+}
+
+func TestWalkInvalidNames(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test names can't be created on Windows")
+	}
+
+	dir, err := ioutil.TempDir("", "names")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"fine", "what?", "aux.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfiler := make(fakeCurrentFiler)
+	w := Walker{
+		Dir:             dir,
+		BlockSize:       128 * 1024,
+		Hashers:         2,
+		CurrentFiler:    cfiler,
+		TargetPlatforms: []string{"windows"},
+	}
+
+	files := walkWith(t, &w)
+	if len(files) != 3 {
+		t.Fatalf("expected three files, got %v", files)
+	}
+	expected := map[string]protocol.InvalidReason{
+		"aux.txt": protocol.InvalidReasonReservedName,
+		"fine":    protocol.InvalidReasonNone,
+		"what?":   protocol.InvalidReasonCharacters,
+	}
+	for _, f := range files {
+		if f.IsInvalid() != (expected[f.Name] != protocol.InvalidReasonNone) || f.InvalidReason() != expected[f.Name] {
+			t.Errorf("%q: invalid %v with reason %v, expected reason %v", f.Name, f.IsInvalid(), f.InvalidReason(), expected[f.Name])
+		}
+		cfiler[f.Name] = f
+	}
+
+	// Already invalid files are not announced again.
+	if files := walkWith(t, &w); len(files) != 0 {
+		t.Errorf("unexpected rescan result %v", files)
+	}
+}
+
+func TestWalkAttributeLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attrs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	old := time.Now().Add(-time.Hour)
+	for name, size := range map[string]int{"small": 10, "large": 1000, "recent": 10} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if name != "recent" {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	cfiler := make(fakeCurrentFiler)
+	w := Walker{
+		Dir:          dir,
+		BlockSize:    128 * 1024,
+		Hashers:      2,
+		CurrentFiler: cfiler,
+		MaxFileSize:  100,
+		MinFileAge:   time.Minute,
+	}
+
+	files := walkWith(t, &w)
+	if len(files) != 3 {
+		t.Fatalf("expected three files, got %v", files)
+	}
+	expected := map[string]protocol.InvalidReason{
+		"large":  protocol.InvalidReasonTooLarge,
+		"recent": protocol.InvalidReasonTooRecent,
+		"small":  protocol.InvalidReasonNone,
+	}
+	for _, f := range files {
+		if f.IsInvalid() != (expected[f.Name] != protocol.InvalidReasonNone) || f.InvalidReason() != expected[f.Name] {
+			t.Errorf("%q: invalid %v with reason %v, expected reason %v", f.Name, f.IsInvalid(), f.InvalidReason(), expected[f.Name])
+		}
+		cfiler[f.Name] = f
+	}
+
+	// Once the file has been left alone it is hashed like any other.
+	if err := os.Chtimes(filepath.Join(dir, "recent"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	files = walkWith(t, &w)
+	if len(files) != 1 || files[0].Name != "recent" || files[0].IsInvalid() || len(files[0].Blocks) != 1 {
+		t.Errorf("unexpected rescan result %v", files)
+	}
+}
+
 func TestWalk(t *testing.T) {
 	ignores := ignore.New(false)
 	err := ignores.Load("testdata/.stignore")
@@ -152,7 +434,7 @@ func TestVerify(t *testing.T) {
 	buf := bytes.NewBuffer(data)
 	var progress int64
 
-	blocks, err := Blocks(buf, blocksize, 0, &progress)
+	blocks, err := Blocks(buf, blocksize, protocol.SHA256, 0, &progress)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -165,21 +447,21 @@ func TestVerify(t *testing.T) {
 	}
 
 	buf = bytes.NewBuffer(data)
-	err = Verify(buf, blocksize, blocks)
+	err = Verify(buf, blocksize, protocol.SHA256, blocks)
 	t.Log(err)
 	if err != nil {
 		t.Fatal("Unexpected verify failure", err)
 	}
 
 	buf = bytes.NewBuffer(append(data, '\n'))
-	err = Verify(buf, blocksize, blocks)
+	err = Verify(buf, blocksize, protocol.SHA256, blocks)
 	t.Log(err)
 	if err == nil {
 		t.Fatal("Unexpected verify success")
 	}
 
 	buf = bytes.NewBuffer(data[:len(data)-1])
-	err = Verify(buf, blocksize, blocks)
+	err = Verify(buf, blocksize, protocol.SHA256, blocks)
 	t.Log(err)
 	if err == nil {
 		t.Fatal("Unexpected verify success")
@@ -187,7 +469,7 @@ func TestVerify(t *testing.T) {
 
 	data[42] = 42
 	buf = bytes.NewBuffer(data)
-	err = Verify(buf, blocksize, blocks)
+	err = Verify(buf, blocksize, protocol.SHA256, blocks)
 	t.Log(err)
 	if err == nil {
 		t.Fatal("Unexpected verify success")
@@ -10,11 +10,11 @@ import (
 	"time"
 
 	"github.com/syncthing/syncthing/lib/db"
-	"github.com/syndtr/goleveldb/leveldb"
 )
 
 type FolderStatistics struct {
-	LastFile LastFile `json:"lastFile"`
+	LastFile  LastFile  `json:"lastFile"`
+	LastScrub time.Time `json:"lastScrub"`
 }
 
 type FolderStatisticsReference struct {
@@ -28,7 +28,7 @@ type LastFile struct {
 	Deleted  bool      `json:"deleted"`
 }
 
-func NewFolderStatisticsReference(ldb *leveldb.DB, folder string) *FolderStatisticsReference {
+func NewFolderStatisticsReference(ldb *db.Instance, folder string) *FolderStatisticsReference {
 	prefix := string(db.KeyTypeFolderStatistic) + folder
 	return &FolderStatisticsReference{
 		ns:     db.NewNamespacedKV(ldb, prefix),
@@ -62,8 +62,21 @@ func (s *FolderStatisticsReference) ReceivedFile(file string, deleted bool) {
 	s.ns.PutBool("lastFileDeleted", deleted)
 }
 
+func (s *FolderStatisticsReference) GetLastScrub() time.Time {
+	at, _ := s.ns.Time("lastScrubAt")
+	return at
+}
+
+func (s *FolderStatisticsReference) ScrubCompleted() {
+	if debug {
+		l.Debugln("stats.FolderStatisticsReference.ScrubCompleted:", s.folder)
+	}
+	s.ns.PutTime("lastScrubAt", time.Now())
+}
+
 func (s *FolderStatisticsReference) GetStatistics() FolderStatistics {
 	return FolderStatistics{
-		LastFile: s.GetLastFile(),
+		LastFile:  s.GetLastFile(),
+		LastScrub: s.GetLastScrub(),
 	}
 }